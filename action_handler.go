@@ -2,39 +2,109 @@ package detour
 
 import (
 	"net/http"
-	"sync"
+	"reflect"
+	"sync/atomic"
+	"time"
 )
 
 type actionHandler struct {
+	bufferCapacity int64 // accessed atomically (and first, for 64-bit alignment on 32-bit platforms)
+
 	controller            monadicAction
 	generateNewInputModel createModel
+	modelType             reflect.Type
+	responses             []ResponseDeclaration
+	contract              ContractMode
+	compressionThreshold  int
+	spillThreshold        int
+	settings              []setting
+	cors                  *corsPolicy
+	timeout               time.Duration
+	timeoutRenderer       Renderer
+	observers             []func(Outcome)
 }
 
 // Install merely allows *actionHandler to implement a non-public/internal, company-specific interface.
 // Deprecated
 func (this *actionHandler) Install(http.Handler) {}
 
-var buffers = sync.Pool{New: func() interface{} { return newResponseBuffer() }}
-
 func (this *actionHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	started := time.Now()
+	request = this.applySettings(request)
+	outcome := this.serve(response, request)
+	this.observe(outcome, request, started)
+}
+
+// serve skips the remaining stages (including writing the response) once the
+// request context is done (ie. the client disconnected).
+func (this *actionHandler) serve(response http.ResponseWriter, request *http.Request) Outcome {
+	if this.cors != nil {
+		if statusCode := this.cors.preflight(response, request); statusCode > 0 {
+			return Outcome{StatusCode: statusCode}
+		}
+	}
+	if abandoned(request) {
+		return abandon(request)
+	}
+
 	model := this.generateNewInputModel()
-	status, err := prepareInputModel(model, request)
-	result := this.determineResult(model, status, err)
-	buffer := buffers.Get().(*responseBuffer)
+	result, prepared := this.respondWithin(model, request)
+	if abandoned(request) {
+		return abandon(request)
+	}
+
+	buffer := buffers.get(int(atomic.LoadInt64(&this.bufferCapacity)))
+	buffer.spillThreshold = this.spillThreshold
 	result.Render(buffer, request)
-	buffer.flush(response)
-	buffers.Put(buffer)
+	if prepared {
+		this.verifyResponse(result, buffer, request)
+	}
+	if this.cors != nil {
+		this.cors.decorate(buffer.Header(), request)
+	}
+	// Compressing first gives a 304 the same Vary and ETag headers as the 200 it replaces.
+	buffer.compress(request.Header.Get(acceptEncodingHeader), this.compressionThreshold)
+	if isNotModified(request, buffer) {
+		buffer.notModified()
+	}
+	if abandoned(request) {
+		buffer.initialize()
+		this.release(buffer)
+		return abandon(request)
+	}
+
+	outcome := Outcome{StatusCode: buffer.StatusCode()}
+	buffer.flush(response, request)
+	this.release(buffer)
+	return outcome
 }
 
-func (this *actionHandler) determineResult(model interface{}, status int, err error) Renderer {
+func (this *actionHandler) release(buffer *responseBuffer) {
+	atomic.StoreInt64(&this.bufferCapacity, int64(buffer.capacity()))
+	buffers.put(buffer)
+}
+
+// respond provides the result for the request, reporting whether it came from the controller.
+func (this *actionHandler) respond(model interface{}, request *http.Request) (Renderer, bool) {
+	if restricted := restrictMethods(model, request); restricted != nil {
+		return restricted, false
+	}
+	status, err := prepareInputModel(model, request)
 	if err != nil {
-		return inputModelErrorResult(status, err)
-	} else {
-		return this.controllerActionResult(model)
+		return inputModelErrorResult(status, err), false
+	}
+	if abandoned(request) { // the client left (or the deadline passed) while preparing the model
+		return IfElseRenderer(this.timeout > 0, this.timeoutRenderer, NopRenderer{}), false
 	}
+	return this.callWithin(model, request)
 }
 
 func inputModelErrorResult(code int, err error) Renderer {
+	if err == errMethodNotAllowed {
+		// JSON input models may only be bound from POST and PUT requests (see bindJSON).
+		return CompoundRenderer{allowHeader(jsonMethods), &StatusCodeResult{StatusCode: code, Message: err.Error()}}
+	}
+
 	_, isErrors := err.(Errors)
 	if isErrors {
 		return &JSONResult{StatusCode: code, Content: err}
@@ -94,6 +94,20 @@ func (this *ModelBinderFixture) TestBindsModelAndHandlesNilErrors() {
 	this.So(this.response.Code, should.Equal, 200)
 }
 
+func (this *ModelBinderFixture) TestBindsModelDiagnosticErrors_VerboseDiagnostics__HTTP400WithMessage() {
+	binder := New(this.controller.HandleBindingFailsInputModelWithDiagnosticErrors, VerboseDiagnostics(true))
+	binder.ServeHTTP(this.response, this.request)
+	this.So(this.response.Code, should.Equal, http.StatusBadRequest)
+	this.So(this.response.Body.String(), should.EqualTrimSpace, "Bad Request\n\nErrors:\n\n- BindingFailsInputModel")
+}
+
+func (this *ModelBinderFixture) TestBindsModelDiagnosticErrors_QuietDiagnostics__HTTP400WithStatusText() {
+	binder := New(this.controller.HandleBindingFailsInputModelWithDiagnosticErrors)
+	binder.ServeHTTP(this.response, this.request)
+	this.So(this.response.Code, should.Equal, http.StatusBadRequest)
+	this.So(this.response.Body.String(), should.EqualTrimSpace, "Bad Request")
+}
+
 func (this *ModelBinderFixture) TestBindsModelEmptyValidationErrors__HTTP200() {
 	binder := New(this.controller.HandleBindingSucceedsInputModelWithEmptyDiagnosticErrors)
 	binder.ServeHTTP(this.response, this.request)
@@ -145,6 +159,7 @@ func (this *ModelBinderFixture) TestBindFromJSON_NotPUTorPOST_MethodNotAllowed()
 	binder.ServeHTTP(this.response, this.request)
 	this.So(this.response.Code, should.Equal, 405)
 	this.So(this.response.Body.String(), should.Equal, "Method Not Allowed")
+	this.So(this.response.Header().Get("Allow"), should.Equal, "POST, PUT")
 }
 
 func (this *ModelBinderFixture) TestBindFromJSONPut() {
@@ -0,0 +1,123 @@
+package detour
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// bufferPool retains responseBuffers for reuse, segregated into size classes by
+// capacity so that handlers which render small responses aren't handed (and
+// don't pin) buffers grown by handlers which render large ones. Buffers whose
+// capacity exceeds the maximum are discarded rather than retained.
+type bufferPool struct {
+	// accessed atomically (and first, for 64-bit alignment on 32-bit platforms)
+	gets        uint64
+	hits        uint64
+	misses      uint64
+	discarded   uint64
+	peak        int64
+	maxCapacity int64
+
+	classes []int // the (inclusive) upper bound of capacity for each class but the last
+	pools   []retainer
+}
+
+// retainer is satisfied by *sync.Pool.
+type retainer interface {
+	Get() interface{}
+	Put(interface{})
+}
+
+func newBufferPool(maxCapacity int, classes ...int) *bufferPool {
+	pools := make([]retainer, len(classes)+1)
+	for class := range pools {
+		pools[class] = new(sync.Pool)
+	}
+	return &bufferPool{
+		maxCapacity: int64(maxCapacity),
+		classes:     classes,
+		pools:       pools,
+	}
+}
+
+// get provides a buffer from the class suited to the capacity hint (ie. the
+// capacity of the buffer last used by the same handler).
+func (this *bufferPool) get(hint int) *responseBuffer {
+	atomic.AddUint64(&this.gets, 1)
+	if buffer, ok := this.pools[this.class(hint)].Get().(*responseBuffer); ok {
+		atomic.AddUint64(&this.hits, 1)
+		return buffer
+	}
+	atomic.AddUint64(&this.misses, 1)
+	return newResponseBuffer()
+}
+
+// put retains the (initialized) buffer unless its capacity exceeds the maximum.
+func (this *bufferPool) put(buffer *responseBuffer) {
+	capacity := int64(buffer.capacity())
+	for peak := atomic.LoadInt64(&this.peak); capacity > peak; peak = atomic.LoadInt64(&this.peak) {
+		if atomic.CompareAndSwapInt64(&this.peak, peak, capacity) {
+			break
+		}
+	}
+
+	if maxCapacity := atomic.LoadInt64(&this.maxCapacity); maxCapacity > 0 && capacity > maxCapacity {
+		atomic.AddUint64(&this.discarded, 1)
+		return
+	}
+	this.pools[this.class(int(capacity))].Put(buffer)
+}
+
+func (this *bufferPool) class(capacity int) int {
+	for class, bound := range this.classes {
+		if capacity <= bound {
+			return class
+		}
+	}
+	return len(this.classes)
+}
+
+func (this *bufferPool) statistics() BufferPoolStatistics {
+	return BufferPoolStatistics{
+		Gets:         atomic.LoadUint64(&this.gets),
+		Hits:         atomic.LoadUint64(&this.hits),
+		Misses:       atomic.LoadUint64(&this.misses),
+		Discarded:    atomic.LoadUint64(&this.discarded),
+		PeakCapacity: int(atomic.LoadInt64(&this.peak)),
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// BufferPoolStatistics describe the reuse of the buffers into which all handlers render responses.
+type BufferPoolStatistics struct {
+	Gets         uint64 // buffers requested
+	Hits         uint64 // requests satisfied by a retained buffer
+	Misses       uint64 // requests satisfied by a new buffer
+	Discarded    uint64 // buffers not retained because their capacity exceeded the maximum
+	PeakCapacity int    // the capacity (in bytes) of the largest buffer released thus far
+}
+
+// HitRate is the fraction of Gets satisfied by a retained buffer.
+func (this BufferPoolStatistics) HitRate() float64 {
+	if this.Gets == 0 {
+		return 0
+	}
+	return float64(this.Hits) / float64(this.Gets)
+}
+
+// BufferPoolStats reports statistics for tuning SetMaxBufferCapacity.
+func BufferPoolStats() BufferPoolStatistics {
+	return buffers.statistics()
+}
+
+// SetMaxBufferCapacity limits the capacity (in bytes) of buffers retained for reuse.
+// Buffers grown beyond the limit (by rendering a large response) are left to the
+// garbage collector. A limit of zero (or less) retains all buffers.
+func SetMaxBufferCapacity(maxCapacity int) {
+	atomic.StoreInt64(&buffers.maxCapacity, int64(maxCapacity))
+}
+
+var buffers = newBufferPool(defaultMaxBufferCapacity, 4<<10, 64<<10, 1<<20)
+
+const defaultMaxBufferCapacity = 4 << 20
@@ -0,0 +1,167 @@
+package detour
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestBufferPoolFixture(t *testing.T) {
+	gunit.Run(new(BufferPoolFixture), t)
+}
+
+type BufferPoolFixture struct {
+	*gunit.Fixture
+
+	pool *bufferPool
+}
+
+func (this *BufferPoolFixture) Setup() {
+	this.pool = newBufferPool(1024, 64, 256)
+	for class := range this.pool.pools {
+		this.pool.pools[class] = new(FakeRetainer) // sync.Pool may drop buffers at any time
+	}
+}
+
+func grownBuffer(size int) *responseBuffer {
+	buffer := newResponseBuffer()
+	buffer.body.Grow(size)
+	buffer.initialize()
+	return buffer
+}
+
+func (this *BufferPoolFixture) TestEmptyPool_Miss() {
+	buffer := this.pool.get(0)
+
+	this.So(buffer, should.NotBeNil)
+	this.So(this.pool.statistics(), should.Resemble, BufferPoolStatistics{Gets: 1, Misses: 1})
+}
+
+func (this *BufferPoolFixture) TestRetainedBuffer_Hit() {
+	buffer := grownBuffer(100)
+	this.pool.put(buffer)
+
+	this.So(this.pool.get(200), should.Equal, buffer)
+	this.So(this.pool.statistics(), should.Resemble, BufferPoolStatistics{Gets: 1, Hits: 1, PeakCapacity: buffer.capacity()})
+	this.So(this.pool.statistics().HitRate(), should.Equal, 1.0)
+}
+
+func (this *BufferPoolFixture) TestBuffersSegregatedBySizeClass() {
+	small := grownBuffer(10)
+	large := grownBuffer(500)
+	this.pool.put(small)
+	this.pool.put(large)
+
+	this.So(this.pool.get(100), should.NotBeIn, []*responseBuffer{small, large})
+	this.So(this.pool.get(1000), should.Equal, large)
+	this.So(this.pool.get(0), should.Equal, small)
+	this.So(this.pool.statistics().Misses, should.Equal, 1)
+}
+
+func (this *BufferPoolFixture) TestSizeClasses() {
+	this.So(this.pool.class(0), should.Equal, 0)
+	this.So(this.pool.class(64), should.Equal, 0)
+	this.So(this.pool.class(65), should.Equal, 1)
+	this.So(this.pool.class(256), should.Equal, 1)
+	this.So(this.pool.class(257), should.Equal, 2)
+	this.So(this.pool.class(1<<30), should.Equal, 2)
+}
+
+func (this *BufferPoolFixture) TestExcessiveCapacity_Discarded() {
+	huge := grownBuffer(2048)
+	this.pool.put(huge)
+
+	this.So(this.pool.get(2048), should.NotEqual, huge)
+	statistics := this.pool.statistics()
+	this.So(statistics.Discarded, should.Equal, 1)
+	this.So(statistics.PeakCapacity, should.BeGreaterThanOrEqualTo, 2048)
+}
+
+func (this *BufferPoolFixture) TestNoMaximum_AllRetained() {
+	this.pool.maxCapacity = 0
+	huge := grownBuffer(2048)
+	this.pool.put(huge)
+
+	this.So(this.pool.get(2048), should.Equal, huge)
+	this.So(this.pool.statistics().Discarded, should.Equal, 0)
+}
+
+func (this *BufferPoolFixture) TestHitRate_NoGets() {
+	this.So(BufferPoolStatistics{}.HitRate(), should.Equal, 0)
+}
+
+func (this *BufferPoolFixture) TestHandlerReleasesBufferWithCapacityHint() {
+	handler := New(func() Renderer { return BytesBodyRenderer(bytes.Repeat([]byte("x"), 10000)) }).(*actionHandler)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	this.So(handler.bufferCapacity, should.BeGreaterThanOrEqualTo, 10000)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// The mixed workloads render one large (8 MB) response for every 100 small
+// (1 KB) ones: compare allocations with (bounded) and without (unbounded) a
+// maximum retained capacity.
+
+func BenchmarkSmallResponses(b *testing.B) {
+	benchmarkResponses(b, defaultMaxBufferCapacity, 0)
+}
+func BenchmarkMixedResponses_Bounded(b *testing.B) {
+	benchmarkResponses(b, defaultMaxBufferCapacity, 100)
+}
+func BenchmarkMixedResponses_Unbounded(b *testing.B) {
+	benchmarkResponses(b, 0, 100)
+}
+
+func benchmarkResponses(b *testing.B, maxCapacity, largeEvery int) {
+	SetMaxBufferCapacity(maxCapacity)
+	defer SetMaxBufferCapacity(defaultMaxBufferCapacity)
+
+	small := New(func() Renderer { return BytesBodyRenderer(make([]byte, 1<<10)) })
+	large := New(func() Renderer { return BytesBodyRenderer(make([]byte, 8<<20)) })
+	request := httptest.NewRequest("GET", "/", nil)
+	response := new(discardingResponseWriter)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for x := 0; x < b.N; x++ {
+		if largeEvery > 0 && x%largeEvery == 0 {
+			large.ServeHTTP(response, request)
+		} else {
+			small.ServeHTTP(response, request)
+		}
+	}
+}
+
+// FakeRetainer (unlike sync.Pool) reliably retains every buffer it's given.
+type FakeRetainer struct{ retained []interface{} }
+
+func (this *FakeRetainer) Put(value interface{}) { this.retained = append(this.retained, value) }
+func (this *FakeRetainer) Get() interface{} {
+	if len(this.retained) == 0 {
+		return nil
+	}
+	value := this.retained[len(this.retained)-1]
+	this.retained = this.retained[:len(this.retained)-1]
+	return value
+}
+
+type discardingResponseWriter struct{ header http.Header }
+
+func (this *discardingResponseWriter) Header() http.Header {
+	if this.header == nil {
+		this.header = make(http.Header)
+	}
+	return this.header
+}
+func (this *discardingResponseWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (this *discardingResponseWriter) WriteHeader(int) {
+	for key := range this.header {
+		delete(this.header, key)
+	}
+}
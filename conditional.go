@@ -0,0 +1,90 @@
+package detour
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// writeValidators sets the ETag and Last-Modified headers used to answer conditional
+// requests (see actionHandler.ServeHTTP). An explicit etag takes precedence over
+// one generated from the content.
+func writeValidators(response http.ResponseWriter, etag string, generate bool, content []byte, lastModified time.Time) {
+	headers := response.Header()
+	if len(etag) > 0 {
+		headers.Set(etagHeader, quoteETag(etag))
+	} else if generate {
+		headers.Set(etagHeader, generateETag(content))
+	}
+	if !lastModified.IsZero() {
+		headers.Set(lastModifiedHeader, lastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+func generateETag(content []byte) string {
+	sum := sha256.Sum256(content)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// isNotModified determines whether the (successful) buffered response to a GET or
+// HEAD request may be replaced with a 304 Not Modified (RFC 7232, section 6).
+func isNotModified(request *http.Request, buffer *responseBuffer) bool {
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		return false
+	}
+	if buffer.statusCode != http.StatusOK {
+		return false
+	}
+
+	if noneMatch := request.Header.Get(ifNoneMatchHeader); len(noneMatch) > 0 {
+		return etagListMatches(noneMatch, buffer.headers.Get(etagHeader))
+	}
+	return notModifiedSince(request.Header.Get(ifModifiedSinceHeader), buffer.headers.Get(lastModifiedHeader))
+}
+
+func etagListMatches(list, etag string) bool {
+	if len(etag) == 0 {
+		return false
+	}
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || weakETag(candidate) == weakETag(etag) {
+			return true
+		}
+	}
+	return false
+}
+func weakETag(etag string) string {
+	return strings.TrimPrefix(etag, "W/")
+}
+
+func notModifiedSince(ifModifiedSince, lastModified string) bool {
+	if len(ifModifiedSince) == 0 || len(lastModified) == 0 {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(since)
+}
+
+const (
+	etagHeader            = "ETag"
+	lastModifiedHeader    = "Last-Modified"
+	ifNoneMatchHeader     = "If-None-Match"
+	ifModifiedSinceHeader = "If-Modified-Since"
+)
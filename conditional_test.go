@@ -0,0 +1,131 @@
+package detour
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestConditionalRequestFixture(t *testing.T) {
+	gunit.Run(new(ConditionalRequestFixture), t)
+}
+
+type ConditionalRequestFixture struct {
+	*gunit.Fixture
+
+	request  *http.Request
+	response *httptest.ResponseRecorder
+	modified time.Time
+}
+
+func (this *ConditionalRequestFixture) Setup() {
+	this.request = httptest.NewRequest("GET", "/", nil)
+	this.response = httptest.NewRecorder()
+	this.modified = time.Date(2020, 1, 2, 3, 4, 5, 600, time.UTC)
+}
+
+func (this *ConditionalRequestFixture) serve(result Renderer) {
+	New(func() Renderer { return result }).ServeHTTP(this.response, this.request)
+}
+func (this *ConditionalRequestFixture) assertNotModified() {
+	this.So(this.response.Code, should.Equal, http.StatusNotModified)
+	this.So(this.response.Body.String(), should.BeBlank)
+	this.So(this.response.Header().Get(contentTypeHeader), should.BeBlank)
+}
+func (this *ConditionalRequestFixture) assertFullResponse() {
+	this.So(this.response.Code, should.Equal, http.StatusOK)
+	this.So(this.response.Body.String(), should.NotBeBlank)
+}
+
+func (this *ConditionalRequestFixture) TestMatchingGeneratedETag_NotModified() {
+	this.request.Header.Set(ifNoneMatchHeader, `"other", `+generateETag([]byte(`{"a":1}`+"\n")))
+
+	this.serve(JSONResult{Content: map[string]int{"a": 1}, GenerateETag: true, Header: http.Header{"Cache-Control": {"max-age=60"}}})
+
+	this.assertNotModified()
+	this.So(this.response.Header().Get(etagHeader), should.NotBeBlank)
+	this.So(this.response.Header().Get("Cache-Control"), should.Equal, "max-age=60")
+}
+
+func (this *ConditionalRequestFixture) TestCompressibleResponse_NotModifiedKeepsVaryAndETag() {
+	handler := New(func() Renderer { return ContentResult{Content: "Hello, World!", ETag: "v1"} }, CompressResponses(10))
+	this.request.Header.Set(acceptEncodingHeader, "gzip")
+	handler.ServeHTTP(this.response, this.request)
+	full := this.response.Header()
+
+	this.response = httptest.NewRecorder()
+	this.request.Header.Set(ifNoneMatchHeader, full.Get(etagHeader))
+	handler.ServeHTTP(this.response, this.request)
+
+	this.assertNotModified()
+	this.So(full.Get(contentEncodingHeader), should.Equal, "gzip")
+	this.So(this.response.Header().Get(contentEncodingHeader), should.BeBlank)
+	this.So(this.response.Header().Get(varyHeader), should.Equal, full.Get(varyHeader))
+	this.So(this.response.Header().Get(etagHeader), should.Equal, `W/"v1"`)
+}
+
+func (this *ConditionalRequestFixture) TestDifferentETag_FullResponse() {
+	this.request.Header.Set(ifNoneMatchHeader, `"other"`)
+	this.serve(ContentResult{Content: "hi", ETag: "v1"})
+	this.assertFullResponse()
+}
+
+func (this *ConditionalRequestFixture) TestWeakComparisonAndWildcard() {
+	this.request.Header.Set(ifNoneMatchHeader, `W/"v1"`)
+	this.serve(ContentResult{Content: "hi", ETag: "v1"})
+	this.assertNotModified()
+
+	this.response = httptest.NewRecorder()
+	this.request.Header.Set(ifNoneMatchHeader, `*`)
+	this.serve(ContentResult{Content: "hi", ETag: "v1"})
+	this.assertNotModified()
+}
+
+func (this *ConditionalRequestFixture) TestWildcardWithoutETag_FullResponse() {
+	this.request.Header.Set(ifNoneMatchHeader, `*`)
+	this.serve(ContentResult{Content: "hi"})
+	this.assertFullResponse()
+}
+
+func (this *ConditionalRequestFixture) TestNotModifiedSince() {
+	this.request.Header.Set(ifModifiedSinceHeader, this.modified.Format(http.TimeFormat))
+
+	this.serve(BinaryResult{Content: []byte("hi"), LastModified: this.modified})
+
+	this.assertNotModified()
+	this.So(this.response.Header().Get(lastModifiedHeader), should.Equal, this.modified.Format(http.TimeFormat))
+}
+
+func (this *ConditionalRequestFixture) TestModifiedSince_FullResponse() {
+	this.request.Header.Set(ifModifiedSinceHeader, this.modified.Add(-time.Second).Format(http.TimeFormat))
+	this.serve(BinaryResult{Content: []byte("hi"), LastModified: this.modified})
+	this.assertFullResponse()
+}
+
+func (this *ConditionalRequestFixture) TestIfNoneMatchTakesPrecedenceOverIfModifiedSince() {
+	this.request.Header.Set(ifNoneMatchHeader, `"other"`)
+	this.request.Header.Set(ifModifiedSinceHeader, this.modified.Format(http.TimeFormat))
+	this.serve(ContentResult{Content: "hi", ETag: "v1", LastModified: this.modified})
+	this.assertFullResponse()
+}
+
+func (this *ConditionalRequestFixture) TestUnparseableDates_FullResponse() {
+	this.request.Header.Set(ifModifiedSinceHeader, "yesterday")
+	this.serve(ContentResult{Content: "hi", LastModified: this.modified})
+	this.assertFullResponse()
+}
+
+func (this *ConditionalRequestFixture) TestOnlySuccessfulGETAndHEAD() {
+	this.request.Header.Set(ifNoneMatchHeader, `"v1"`)
+	this.serve(ContentResult{StatusCode: http.StatusCreated, Content: "hi", ETag: "v1"})
+	this.So(this.response.Code, should.Equal, http.StatusCreated)
+
+	this.response = httptest.NewRecorder()
+	this.request.Method = http.MethodPost
+	this.serve(ContentResult{Content: "hi", ETag: "v1"})
+	this.assertFullResponse()
+}
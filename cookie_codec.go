@@ -0,0 +1,154 @@
+package detour
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// CookieKey is one generation of the secrets used to protect cookie values.
+type CookieKey struct {
+	Signing    []byte // HMAC-SHA256 key (required; at least 32 bytes is recommended)
+	Encryption []byte // optional AES-128/192/256 key (16, 24, or 32 bytes) enabling AES-GCM encryption
+}
+
+// CookieCodec signs (and optionally encrypts) cookie values so that they can't be
+// forged, tampered with, or (when encrypted) read by the client. Values are always
+// encoded with the first key and decoded with whichever key succeeds, allowing for
+// keys to be rotated: prepend the new key and remove the old one after it expires.
+type CookieCodec struct {
+	keys []cookieKey
+}
+
+type cookieKey struct {
+	signing []byte
+	aead    cipher.AEAD
+}
+
+func NewCookieCodec(keys ...CookieKey) (*CookieCodec, error) {
+	if len(keys) == 0 {
+		return nil, errNoCookieKeys
+	}
+	codec := new(CookieCodec)
+	for _, key := range keys {
+		if len(key.Signing) == 0 {
+			return nil, errNoCookieSigningKey
+		}
+		prepared := cookieKey{signing: key.Signing}
+		if len(key.Encryption) > 0 {
+			block, err := aes.NewCipher(key.Encryption)
+			if err != nil {
+				return nil, err
+			}
+			if prepared.aead, err = cipher.NewGCM(block); err != nil {
+				return nil, err
+			}
+		}
+		codec.keys = append(codec.keys, prepared)
+	}
+	return codec, nil
+}
+
+// Encode protects the value of the named cookie. The name is included in the
+// signature so that a value can't be moved from one cookie to another.
+func (this *CookieCodec) Encode(name, value string) (string, error) {
+	if this == nil || len(this.keys) == 0 {
+		return "", errNoCookieKeys // ie. a nil or zero-value codec (see NewCookieCodec)
+	}
+	key := this.keys[0]
+	payload := []byte(value)
+	if key.aead != nil {
+		nonce := make([]byte, key.aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return "", err
+		}
+		payload = key.aead.Seal(nonce, nonce, payload, []byte(name))
+	}
+	encoded := cookieEncoding.EncodeToString(payload)
+	return encoded + "." + cookieEncoding.EncodeToString(key.sign(name, encoded)), nil
+}
+
+// Decode verifies (and decrypts) a value produced by Encode for the named cookie.
+func (this *CookieCodec) Decode(name, encoded string) (string, error) {
+	separator := strings.LastIndex(encoded, ".")
+	if separator < 0 {
+		return "", errInvalidCookie
+	}
+	payload, signature := encoded[:separator], encoded[separator+1:]
+	mac, err := cookieEncoding.DecodeString(signature)
+	if err != nil {
+		return "", errInvalidCookie
+	}
+
+	if this == nil {
+		return "", errInvalidCookie
+	}
+	for _, key := range this.keys {
+		if !hmac.Equal(mac, key.sign(name, payload)) {
+			continue
+		}
+		return key.open(name, payload)
+	}
+	return "", errInvalidCookie
+}
+
+// Cookie reads, verifies, and decodes the named cookie from the request, returning
+// http.ErrNoCookie if it's missing or a 400 *InputError if it's been tampered with.
+func (this *CookieCodec) Cookie(request *http.Request, name string) (string, error) {
+	cookie, err := request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	value, err := this.Decode(name, cookie.Value)
+	if err != nil {
+		return "", invalidCookieError(name)
+	}
+	return value, nil
+}
+
+func (this cookieKey) sign(name, payload string) []byte {
+	mac := hmac.New(sha256.New, this.signing)
+	_, _ = io.WriteString(mac, name)
+	_, _ = io.WriteString(mac, "|")
+	_, _ = io.WriteString(mac, payload)
+	return mac.Sum(nil)
+}
+
+func (this cookieKey) open(name, payload string) (string, error) {
+	decoded, err := cookieEncoding.DecodeString(payload)
+	if err != nil {
+		return "", errInvalidCookie
+	}
+	if this.aead == nil {
+		return string(decoded), nil
+	}
+
+	size := this.aead.NonceSize()
+	if len(decoded) < size {
+		return "", errInvalidCookie
+	}
+	plaintext, err := this.aead.Open(nil, decoded[:size], decoded[size:], []byte(name))
+	if err != nil {
+		return "", errInvalidCookie
+	}
+	return string(plaintext), nil
+}
+
+func invalidCookieError(name string) error {
+	return &InputError{Fields: []string{name}, Message: "Invalid cookie", HTTPStatusCode: http.StatusBadRequest}
+}
+
+var (
+	cookieEncoding = base64.RawURLEncoding
+
+	errNoCookieKeys       = errors.New("at least one cookie key is required")
+	errNoCookieSigningKey = errors.New("cookie keys require a signing key")
+	errInvalidCookie      = errors.New("invalid cookie")
+)
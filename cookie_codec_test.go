@@ -0,0 +1,122 @@
+package detour
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestCookieCodecFixture(t *testing.T) {
+	gunit.Run(new(CookieCodecFixture), t)
+}
+
+type CookieCodecFixture struct {
+	*gunit.Fixture
+
+	signed    *CookieCodec
+	encrypted *CookieCodec
+}
+
+func (this *CookieCodecFixture) Setup() {
+	this.signed, _ = NewCookieCodec(CookieKey{Signing: []byte("signing-key")})
+	this.encrypted, _ = NewCookieCodec(CookieKey{Signing: []byte("signing-key"), Encryption: []byte("0123456789abcdef")})
+}
+
+func (this *CookieCodecFixture) TestInvalidKeys() {
+	_, err := NewCookieCodec()
+	this.So(err, should.Equal, errNoCookieKeys)
+
+	_, err = NewCookieCodec(CookieKey{Encryption: []byte("0123456789abcdef")})
+	this.So(err, should.Equal, errNoCookieSigningKey)
+
+	_, err = NewCookieCodec(CookieKey{Signing: []byte("key"), Encryption: []byte("short")})
+	this.So(err, should.NotBeNil)
+}
+
+func (this *CookieCodecFixture) TestSignedRoundTrip() {
+	encoded, err := this.signed.Encode("session", "user-42")
+	this.So(err, should.BeNil)
+
+	decoded, err := this.signed.Decode("session", encoded)
+
+	this.So(err, should.BeNil)
+	this.So(decoded, should.Equal, "user-42")
+}
+
+func (this *CookieCodecFixture) TestEncryptedRoundTrip_ValueNotVisible() {
+	encoded, err := this.encrypted.Encode("session", "user-42")
+	this.So(err, should.BeNil)
+	this.So(encoded, should.NotContainSubstring, "user-42")
+	this.So(encoded, should.NotContainSubstring, cookieEncoding.EncodeToString([]byte("user-42")))
+
+	decoded, err := this.encrypted.Decode("session", encoded)
+
+	this.So(err, should.BeNil)
+	this.So(decoded, should.Equal, "user-42")
+}
+
+func (this *CookieCodecFixture) TestTamperedValuesRejected() {
+	for _, codec := range []*CookieCodec{this.signed, this.encrypted} {
+		encoded, _ := codec.Encode("session", "user-42")
+		separator := strings.LastIndex(encoded, ".")
+		forged := cookieEncoding.EncodeToString([]byte("admin")) + encoded[separator:]
+
+		_, err := codec.Decode("session", forged)
+		this.So(err, should.Equal, errInvalidCookie)
+
+		_, err = codec.Decode("other-cookie", encoded)
+		this.So(err, should.Equal, errInvalidCookie)
+
+		_, err = codec.Decode("session", encoded[:separator])
+		this.So(err, should.Equal, errInvalidCookie)
+
+		_, err = codec.Decode("session", encoded+"x")
+		this.So(err, should.Equal, errInvalidCookie)
+	}
+}
+
+func (this *CookieCodecFixture) TestDifferentKey_Rejected() {
+	encoded, _ := this.signed.Encode("session", "user-42")
+	other, _ := NewCookieCodec(CookieKey{Signing: []byte("other-key")})
+
+	_, err := other.Decode("session", encoded)
+
+	this.So(err, should.Equal, errInvalidCookie)
+}
+
+func (this *CookieCodecFixture) TestKeyRotation() {
+	old := CookieKey{Signing: []byte("old-signing"), Encryption: []byte("0123456789abcdef")}
+	current := CookieKey{Signing: []byte("new-signing")}
+	before, _ := NewCookieCodec(old)
+	after, _ := NewCookieCodec(current, old)
+	encodedBefore, _ := before.Encode("session", "user-42")
+
+	decoded, err := after.Decode("session", encodedBefore)
+	this.So(err, should.BeNil)
+	this.So(decoded, should.Equal, "user-42")
+
+	encodedAfter, _ := after.Encode("session", "user-42")
+	_, err = before.Decode("session", encodedAfter)
+	this.So(err, should.Equal, errInvalidCookie)
+}
+
+func (this *CookieCodecFixture) TestCookieFromRequest() {
+	encoded, _ := this.signed.Encode("session", "user-42")
+	request := httptest.NewRequest("GET", "/", nil)
+	request.AddCookie(&http.Cookie{Name: "session", Value: encoded})
+	request.AddCookie(&http.Cookie{Name: "forged", Value: encoded})
+
+	value, err := this.signed.Cookie(request, "session")
+	this.So(err, should.BeNil)
+	this.So(value, should.Equal, "user-42")
+
+	_, err = this.signed.Cookie(request, "missing")
+	this.So(err, should.Equal, http.ErrNoCookie)
+
+	_, err = this.signed.Cookie(request, "forged")
+	this.So(err, should.Resemble, invalidCookieError("forged"))
+}
@@ -0,0 +1,226 @@
+package detour
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy governs which cross-origin requests browsers may make of a handler (see CORS).
+type CORSPolicy struct {
+	// AllowedOrigins lists exact origins ("https://example.com"), patterns with a
+	// single wildcard ("https://*.example.com"), or "*" to allow any origin
+	// (which can't be combined with AllowCredentials).
+	AllowedOrigins []string
+
+	// AllowedMethods defaults to the methods of a model implementing Methods,
+	// POST and PUT for a model binding JSON, or GET, HEAD, and POST otherwise.
+	AllowedMethods []string
+
+	AllowedHeaders   []string      // request headers beyond the CORS-safelisted headers; "*" allows any
+	ExposedHeaders   []string      // response headers made available to scripts
+	AllowCredentials bool          // allow cookies and HTTP authentication
+	MaxAge           time.Duration // how long browsers may cache preflight results; omitted when zero
+}
+
+// CORS applies the policy to a single handler. Preflight requests are answered
+// without preparing an input model or calling the controller, and all other
+// responses (including those reporting binding and validation errors) receive
+// the headers appropriate to the origin of the request.
+func CORS(policy CORSPolicy) Option {
+	if policy.AllowCredentials {
+		for _, origin := range policy.AllowedOrigins {
+			if origin == "*" {
+				panic("CORS credentials can't be allowed for any origin (\"*\"); list the allowed origins instead.")
+			}
+		}
+	}
+	return func(this *actionHandler) { this.cors = newCORSPolicy(policy, this.modelType) }
+}
+
+type corsPolicy struct {
+	origins     []string
+	anyOrigin   bool
+	credentials bool
+	methods     map[string]bool
+	headers     map[string]bool
+	anyHeader   bool
+
+	allowMethods  string
+	exposeHeaders string
+	maxAge        string
+}
+
+func newCORSPolicy(policy CORSPolicy, modelType reflect.Type) *corsPolicy {
+	this := &corsPolicy{
+		credentials:   policy.AllowCredentials,
+		methods:       make(map[string]bool),
+		headers:       make(map[string]bool),
+		exposeHeaders: strings.Join(policy.ExposedHeaders, ", "),
+	}
+
+	for _, origin := range policy.AllowedOrigins {
+		if origin == "*" {
+			this.anyOrigin = true
+		} else {
+			this.origins = append(this.origins, strings.ToLower(origin))
+		}
+	}
+
+	methods := policy.AllowedMethods
+	if len(methods) == 0 {
+		methods = modelMethods(modelType)
+	}
+	var allowed []string
+	for _, method := range methods {
+		method = strings.ToUpper(method)
+		this.methods[method] = true
+		allowed = append(allowed, method)
+	}
+	this.allowMethods = strings.Join(allowed, ", ")
+
+	for _, header := range corsSafelistedHeaders {
+		this.headers[header] = true
+	}
+	for _, header := range policy.AllowedHeaders {
+		if header == "*" {
+			this.anyHeader = true
+		} else {
+			this.headers[http.CanonicalHeaderKey(header)] = true
+		}
+	}
+
+	if policy.MaxAge > 0 {
+		this.maxAge = strconv.Itoa(int(policy.MaxAge / time.Second))
+	}
+	return this
+}
+
+// modelMethods are the methods a handler of the model type is expected to serve.
+func modelMethods(modelType reflect.Type) []string {
+	if modelType != nil {
+		if restricted, ok := newModel(modelType).(Methods); ok {
+			var methods []string
+			for _, method := range allowedMethods(restricted.Methods()) {
+				if method != http.MethodOptions {
+					methods = append(methods, method)
+				}
+			}
+			return methods
+		}
+		if bindsJSON(modelType) {
+			return jsonMethods
+		}
+	}
+	return []string{http.MethodGet, http.MethodHead, http.MethodPost}
+}
+
+// preflight answers a CORS preflight request, returning the status code of the
+// response (or zero if the request wasn't a preflight request).
+func (this *corsPolicy) preflight(response http.ResponseWriter, request *http.Request) int {
+	origin := request.Header.Get(originHeader)
+	method := request.Header.Get(requestMethodHeader)
+	if request.Method != http.MethodOptions || len(origin) == 0 || len(method) == 0 {
+		return 0
+	}
+
+	header := response.Header()
+	header.Add(varyHeader, originHeader)
+	header.Add(varyHeader, requestMethodHeader)
+	header.Add(varyHeader, requestHeadersHeader)
+
+	requested := request.Header.Get(requestHeadersHeader)
+	if !this.allowsOrigin(origin) || !this.methods[strings.ToUpper(method)] || !this.allowsHeaders(requested) {
+		response.WriteHeader(http.StatusForbidden)
+		return http.StatusForbidden
+	}
+
+	this.writeOrigin(header, origin)
+	header.Set(allowMethodsHeader, this.allowMethods)
+	if len(requested) > 0 {
+		header.Set(allowHeadersHeader, requested)
+	}
+	if len(this.maxAge) > 0 {
+		header.Set(maxAgeHeader, this.maxAge)
+	}
+	response.WriteHeader(http.StatusNoContent)
+	return http.StatusNoContent
+}
+
+// decorate adds the headers appropriate to the origin (if any) of an actual (non-preflight) request.
+func (this *corsPolicy) decorate(header http.Header, request *http.Request) {
+	header.Set(varyHeader, appendToken(header.Get(varyHeader), originHeader))
+
+	origin := request.Header.Get(originHeader)
+	if len(origin) == 0 || !this.allowsOrigin(origin) {
+		return
+	}
+	this.writeOrigin(header, origin)
+	if len(this.exposeHeaders) > 0 {
+		header.Set(exposeHeadersHeader, this.exposeHeaders)
+	}
+}
+
+func (this *corsPolicy) writeOrigin(header http.Header, origin string) {
+	if this.anyOrigin && !this.credentials {
+		header.Set(allowOriginHeader, "*")
+	} else {
+		header.Set(allowOriginHeader, origin)
+	}
+	if this.credentials {
+		header.Set(allowCredentialsHeader, "true")
+	}
+}
+
+func (this *corsPolicy) allowsOrigin(origin string) bool {
+	if this.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, allowed := range this.origins {
+		if matchesOrigin(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesOrigin(pattern, origin string) bool {
+	wildcard := strings.Index(pattern, "*")
+	if wildcard < 0 {
+		return pattern == origin
+	}
+	prefix, suffix := pattern[:wildcard], pattern[wildcard+1:]
+	return len(origin) > len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, prefix) &&
+		strings.HasSuffix(origin, suffix)
+}
+
+func (this *corsPolicy) allowsHeaders(requested string) bool {
+	if this.anyHeader {
+		return true
+	}
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if len(header) > 0 && !this.headers[http.CanonicalHeaderKey(header)] {
+			return false
+		}
+	}
+	return true
+}
+
+var corsSafelistedHeaders = []string{"Accept", "Accept-Language", "Content-Language", "Content-Type"}
+
+const (
+	originHeader           = "Origin"
+	requestMethodHeader    = "Access-Control-Request-Method"
+	requestHeadersHeader   = "Access-Control-Request-Headers"
+	allowOriginHeader      = "Access-Control-Allow-Origin"
+	allowCredentialsHeader = "Access-Control-Allow-Credentials"
+	allowMethodsHeader     = "Access-Control-Allow-Methods"
+	allowHeadersHeader     = "Access-Control-Allow-Headers"
+	exposeHeadersHeader    = "Access-Control-Expose-Headers"
+	maxAgeHeader           = "Access-Control-Max-Age"
+)
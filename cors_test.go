@@ -0,0 +1,226 @@
+package detour
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestCORSFixture(t *testing.T) {
+	gunit.Run(new(CORSFixture), t)
+}
+
+type CORSFixture struct {
+	*gunit.Fixture
+
+	controller *Controller
+	policy     CORSPolicy
+	request    *http.Request
+	response   *httptest.ResponseRecorder
+}
+
+func (this *CORSFixture) Setup() {
+	this.controller = &Controller{}
+	this.policy = CORSPolicy{
+		AllowedOrigins: []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods: []string{"get", "PUT"},
+		AllowedHeaders: []string{"x-api-key"},
+		ExposedHeaders: []string{"X-Request-Id", "ETag"},
+		MaxAge:         10 * time.Minute,
+	}
+	this.response = httptest.NewRecorder()
+}
+
+func (this *CORSFixture) preflight(origin, method, headers string) {
+	this.request = httptest.NewRequest(http.MethodOptions, "/", nil)
+	this.request.Header.Set(originHeader, origin)
+	this.request.Header.Set(requestMethodHeader, method)
+	if len(headers) > 0 {
+		this.request.Header.Set(requestHeadersHeader, headers)
+	}
+	this.serve(New(this.controller.HandleBindingInputModel, CORS(this.policy)))
+}
+func (this *CORSFixture) serve(handler http.Handler) {
+	handler.ServeHTTP(this.response, this.request)
+}
+
+func (this *CORSFixture) TestPreflight_Allowed() {
+	this.preflight("https://app.example.com", "PUT", "X-API-Key, content-type")
+
+	this.So(this.response.Code, should.Equal, http.StatusNoContent)
+	this.So(this.response.Body.Len(), should.Equal, 0)
+	this.So(this.response.Header(), should.Resemble, http.Header{
+		"Vary":                         {originHeader, requestMethodHeader, requestHeadersHeader},
+		"Access-Control-Allow-Origin":  {"https://app.example.com"},
+		"Access-Control-Allow-Methods": {"GET, PUT"},
+		"Access-Control-Allow-Headers": {"X-API-Key, content-type"},
+		"Access-Control-Max-Age":       {"600"},
+	})
+}
+
+func (this *CORSFixture) TestPreflight_AnsweredBeforeBinding() {
+	this.request = httptest.NewRequest(http.MethodOptions, "/", nil)
+	this.request.Header.Set(originHeader, "https://app.example.com")
+	this.request.Header.Set(requestMethodHeader, "GET")
+
+	this.serve(New(this.controller.HandleBindingFailsInputModel, CORS(this.policy)))
+
+	this.So(this.response.Code, should.Equal, http.StatusNoContent)
+}
+
+func (this *CORSFixture) TestPreflight_PatternOrigin() {
+	this.preflight("https://api.example.org", "GET", "")
+
+	this.So(this.response.Code, should.Equal, http.StatusNoContent)
+	this.So(this.response.Header().Get(allowOriginHeader), should.Equal, "https://api.example.org")
+}
+
+func (this *CORSFixture) TestPreflight_DisallowedOrigin_Forbidden() {
+	for _, origin := range []string{"https://evil.com", "https://.example.org", "https://example.org", "https://app.example.com.evil.com"} {
+		this.response = httptest.NewRecorder()
+
+		this.preflight(origin, "GET", "")
+
+		this.So(this.response.Code, should.Equal, http.StatusForbidden)
+		this.So(this.response.Header().Get(allowOriginHeader), should.BeBlank)
+	}
+}
+
+func (this *CORSFixture) TestPreflight_DisallowedMethod_Forbidden() {
+	this.preflight("https://app.example.com", "DELETE", "")
+
+	this.So(this.response.Code, should.Equal, http.StatusForbidden)
+}
+
+func (this *CORSFixture) TestPreflight_DisallowedHeader_Forbidden() {
+	this.preflight("https://app.example.com", "GET", "X-Other")
+
+	this.So(this.response.Code, should.Equal, http.StatusForbidden)
+}
+
+func (this *CORSFixture) TestPreflight_AnyHeader() {
+	this.policy.AllowedHeaders = []string{"*"}
+
+	this.preflight("https://app.example.com", "GET", "X-Other")
+
+	this.So(this.response.Code, should.Equal, http.StatusNoContent)
+	this.So(this.response.Header().Get(allowHeadersHeader), should.Equal, "X-Other")
+}
+
+func (this *CORSFixture) TestPreflight_DefaultMethods() {
+	this.policy.AllowedMethods = nil
+
+	this.preflight("https://app.example.com", "POST", "")
+
+	this.So(this.response.Header().Get(allowMethodsHeader), should.Equal, "GET, HEAD, POST")
+}
+
+func (this *CORSFixture) TestPreflight_MethodsFromModel() {
+	this.policy.AllowedMethods = nil
+	this.request = httptest.NewRequest(http.MethodOptions, "/", nil)
+	this.request.Header.Set(originHeader, "https://app.example.com")
+	this.request.Header.Set(requestMethodHeader, "PATCH")
+
+	this.serve(New(func(*MethodRestrictedModel) Renderer { return nil }, CORS(this.policy)))
+
+	this.So(this.response.Code, should.Equal, http.StatusNoContent)
+	this.So(this.response.Header().Get(allowMethodsHeader), should.Equal, "GET, HEAD, PATCH")
+}
+
+func (this *CORSFixture) TestPreflight_MethodsFromJSONModel() {
+	this.policy.AllowedMethods = nil
+	this.request = httptest.NewRequest(http.MethodOptions, "/", nil)
+	this.request.Header.Set(originHeader, "https://app.example.com")
+	this.request.Header.Set(requestMethodHeader, "PUT")
+
+	this.serve(New(func(*BindingFromJSON) Renderer { return nil }, CORS(this.policy)))
+
+	this.So(this.response.Code, should.Equal, http.StatusNoContent)
+	this.So(this.response.Header().Get(allowMethodsHeader), should.Equal, "POST, PUT")
+}
+
+func (this *CORSFixture) TestOptionsWithoutRequestMethod_NotPreflight() {
+	this.request = httptest.NewRequest(http.MethodOptions, "/", nil)
+	this.request.Header.Set(originHeader, "https://app.example.com")
+
+	this.serve(New(this.controller.HandleBindingInputModel, CORS(this.policy)))
+
+	this.So(this.response.Code, should.Equal, http.StatusOK)
+	this.So(this.response.Body.String(), should.ContainSubstring, "Just handled")
+}
+
+func (this *CORSFixture) TestActualRequest_Allowed() {
+	this.request = httptest.NewRequest(http.MethodGet, "/", nil)
+	this.request.Header.Set(originHeader, "https://app.example.com")
+
+	this.serve(New(this.controller.HandleBindingInputModel, CORS(this.policy)))
+
+	this.So(this.response.Code, should.Equal, http.StatusOK)
+	this.So(this.response.Header().Get(allowOriginHeader), should.Equal, "https://app.example.com")
+	this.So(this.response.Header().Get(exposeHeadersHeader), should.Equal, "X-Request-Id, ETag")
+	this.So(this.response.Header().Get(varyHeader), should.Equal, originHeader)
+	this.So(this.response.Header().Get(allowCredentialsHeader), should.BeBlank)
+}
+
+func (this *CORSFixture) TestActualRequest_ErrorResponsesDecorated() {
+	this.request = httptest.NewRequest(http.MethodGet, "/", nil)
+	this.request.Header.Set(originHeader, "https://app.example.com")
+
+	this.serve(New(this.controller.HandleBindingFailsInputModel, CORS(this.policy)))
+
+	this.So(this.response.Code, should.Equal, http.StatusBadRequest)
+	this.So(this.response.Header().Get(allowOriginHeader), should.Equal, "https://app.example.com")
+}
+
+func (this *CORSFixture) TestActualRequest_DisallowedOrigin_OnlyVary() {
+	this.request = httptest.NewRequest(http.MethodGet, "/", nil)
+	this.request.Header.Set(originHeader, "https://evil.com")
+
+	this.serve(New(this.controller.HandleBindingInputModel, CORS(this.policy)))
+
+	this.So(this.response.Code, should.Equal, http.StatusOK)
+	this.So(this.response.Header().Get(allowOriginHeader), should.BeBlank)
+	this.So(this.response.Header().Get(exposeHeadersHeader), should.BeBlank)
+	this.So(this.response.Header().Get(varyHeader), should.Equal, originHeader)
+}
+
+func (this *CORSFixture) TestAnyOrigin() {
+	this.policy.AllowedOrigins = []string{"*"}
+	this.request = httptest.NewRequest(http.MethodGet, "/", nil)
+	this.request.Header.Set(originHeader, "https://anywhere.com")
+
+	this.serve(New(this.controller.HandleBindingInputModel, CORS(this.policy)))
+
+	this.So(this.response.Header().Get(allowOriginHeader), should.Equal, "*")
+}
+
+func (this *CORSFixture) TestAnyOrigin_WithCredentials_Panics() {
+	this.policy.AllowedOrigins = []string{"https://app.example.com", "*"}
+	this.policy.AllowCredentials = true
+
+	this.So(func() { CORS(this.policy) }, should.Panic)
+}
+
+func (this *CORSFixture) TestCredentials() {
+	this.policy.AllowCredentials = true
+	this.request = httptest.NewRequest(http.MethodGet, "/", nil)
+	this.request.Header.Set(originHeader, "https://app.example.com")
+
+	this.serve(New(this.controller.HandleBindingInputModel, CORS(this.policy)))
+
+	this.So(this.response.Header().Get(allowOriginHeader), should.Equal, "https://app.example.com")
+	this.So(this.response.Header().Get(allowCredentialsHeader), should.Equal, "true")
+}
+
+func (this *CORSFixture) TestPolicyNotModified() {
+	methods := []string{"get", "put"}
+	this.policy.AllowedMethods = methods
+
+	CORS(this.policy)(new(actionHandler))
+
+	this.So(methods, should.Resemble, []string{"get", "put"})
+}
@@ -0,0 +1,6 @@
+// Package detourtest offers helpers for end-to-end testing of detour handlers
+// (or any other http.Handler) without a network connection: a fluent request
+// builder, a recorded response with assertion helpers, and utilities to decode
+// detour.Errors responses for easy comparison. VerifyJSONCodec checks that a
+// detour.JSONCodec produces the same output as encoding/json.
+package detourtest
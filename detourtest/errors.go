@@ -0,0 +1,35 @@
+package detourtest
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/smartystreets/detour"
+)
+
+// DecodeErrors deserializes the body of a detour.Errors response. Because the
+// HTTPStatusCode of each error isn't serialized it will always be zero.
+func DecodeErrors(body []byte) ([]detour.InputError, error) {
+	var errs []detour.InputError
+	err := json.Unmarshal(body, &errs)
+	return errs, err
+}
+
+func equalInputErrors(expected, actual []detour.InputError) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for x := range expected {
+		if expected[x].Message != actual[x].Message || !equalFields(expected[x].Fields, actual[x].Fields) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalFields(expected, actual []string) bool {
+	if len(expected) == 0 && len(actual) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(expected, actual)
+}
@@ -0,0 +1,120 @@
+package detourtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+
+	"github.com/smartystreets/detour"
+)
+
+// Inspection describes the effect of a detour.Renderer, as returned by a controller
+// action, without involving an actual HTTP round-trip.
+type Inspection struct {
+	// Renderers is the flattened list of renderers: the contents of any (nested)
+	// CompoundRenderer, with pointers to renderers dereferenced and nils omitted.
+	Renderers []detour.Renderer
+
+	StatusCode  int
+	Header      http.Header
+	ContentType string
+	Body        []byte
+}
+
+// Inspect renders the renderer against a GET request for "/".
+func Inspect(renderer detour.Renderer) Inspection {
+	return InspectRequest(renderer, httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+// InspectRequest renders the renderer against the request (some renderers,
+// like JSONPResult, depend on the request). Like detour's own handlers, the
+// renderer writes to a buffer where the last status code written wins.
+func InspectRequest(renderer detour.Renderer, request *http.Request) Inspection {
+	writer := newBufferedWriter()
+	renderers := Flatten(renderer)
+	for _, item := range renderers {
+		item.Render(writer, request)
+	}
+
+	return Inspection{
+		Renderers:   renderers,
+		StatusCode:  writer.statusCode,
+		Header:      writer.header,
+		ContentType: writer.header.Get("Content-Type"),
+		Body:        writer.body.Bytes(),
+	}
+}
+
+// DecodeJSON deserializes the rendered body into the target.
+func (this Inspection) DecodeJSON(target interface{}) error {
+	return json.Unmarshal(this.Body, target)
+}
+
+// Find returns the first of the flattened renderers with the same type as
+// the sample (ie. detour.JSONResult{}), or nil when there is none.
+func (this Inspection) Find(sample detour.Renderer) detour.Renderer {
+	expected := reflect.TypeOf(sample)
+	for _, renderer := range this.Renderers {
+		if reflect.TypeOf(renderer) == expected {
+			return renderer
+		}
+	}
+	return nil
+}
+
+// Flatten expands (nested) CompoundRenderers, dereferences pointers to renderers
+// with value receivers (ie. *JSONResult becomes JSONResult), and omits nils.
+func Flatten(renderer detour.Renderer) (flattened []detour.Renderer) {
+	if compound, ok := renderer.(detour.CompoundRenderer); ok {
+		for _, item := range compound {
+			flattened = append(flattened, Flatten(item)...)
+		}
+		return flattened
+	}
+	if compound, ok := renderer.(*detour.CompoundRenderer); ok && compound != nil {
+		return Flatten(*compound)
+	}
+	if renderer = dereference(renderer); renderer == nil {
+		return nil
+	}
+	return append(flattened, renderer)
+}
+func dereference(renderer detour.Renderer) detour.Renderer {
+	value := reflect.ValueOf(renderer)
+	if !value.IsValid() {
+		return nil
+	}
+	if value.Kind() != reflect.Ptr {
+		return renderer
+	}
+	if value.IsNil() {
+		return nil
+	}
+	if element, ok := value.Elem().Interface().(detour.Renderer); ok {
+		return element
+	}
+	return renderer
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type bufferedWriter struct {
+	statusCode int
+	header     http.Header
+	body       *bytes.Buffer
+}
+
+func newBufferedWriter() *bufferedWriter {
+	return &bufferedWriter{
+		statusCode: http.StatusOK,
+		header:     make(http.Header),
+		body:       new(bytes.Buffer),
+	}
+}
+
+func (this *bufferedWriter) StatusCode() int             { return this.statusCode }
+func (this *bufferedWriter) Header() http.Header         { return this.header }
+func (this *bufferedWriter) Write(p []byte) (int, error) { return this.body.Write(p) }
+func (this *bufferedWriter) WriteHeader(statusCode int)  { this.statusCode = statusCode }
@@ -0,0 +1,95 @@
+package detourtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/detour"
+	"github.com/smartystreets/gunit"
+)
+
+func TestInspectFixture(t *testing.T) {
+	gunit.Run(new(InspectFixture), t)
+}
+
+type InspectFixture struct {
+	*gunit.Fixture
+}
+
+func (this *InspectFixture) TestJSONResult() {
+	inspection := Inspect(detour.JSONResult{StatusCode: http.StatusCreated, Content: map[string]int{"a": 1}})
+
+	this.So(inspection.StatusCode, should.Equal, http.StatusCreated)
+	this.So(inspection.ContentType, should.Equal, "application/json; charset=utf-8")
+	var decoded map[string]int
+	this.So(inspection.DecodeJSON(&decoded), should.BeNil)
+	this.So(decoded, should.Resemble, map[string]int{"a": 1})
+}
+
+func (this *InspectFixture) TestPointerResultsDereferenced() {
+	inspection := Inspect(&detour.JSONResult{Content: "hi"})
+
+	this.So(inspection.Renderers, should.Resemble, []detour.Renderer{detour.JSONResult{Content: "hi"}})
+	this.So(inspection.Find(detour.JSONResult{}), should.Resemble, detour.JSONResult{Content: "hi"})
+	this.So(inspection.Find(detour.ContentResult{}), should.BeNil)
+}
+
+func (this *InspectFixture) TestCompoundRenderersFlattened() {
+	var nilResult *detour.JSONResult
+	inspection := Inspect(detour.CompoundRenderer{
+		detour.HeadersRenderer{"X-A": {"1"}},
+		nil,
+		nilResult,
+		&detour.CompoundRenderer{
+			detour.IfElseRenderer(false, detour.StatusCodeRenderer(http.StatusTeapot), detour.StatusCodeRenderer(http.StatusAccepted)),
+			detour.CompoundRenderer{detour.StringBodyRenderer("body")},
+		},
+		detour.StatusCodeRenderer(http.StatusNoContent),
+	})
+
+	this.So(inspection.Renderers, should.Resemble, []detour.Renderer{
+		detour.HeadersRenderer{"X-A": {"1"}},
+		detour.StatusCodeRenderer(http.StatusAccepted),
+		detour.StringBodyRenderer("body"),
+		detour.StatusCodeRenderer(http.StatusNoContent),
+	})
+	this.So(inspection.StatusCode, should.Equal, http.StatusNoContent) // last status code wins
+	this.So(inspection.Header.Get("X-A"), should.Equal, "1")
+	this.So(string(inspection.Body), should.Equal, "body")
+}
+
+func (this *InspectFixture) TestPointerReceiverRenderersKept() {
+	renderer := &PointerRenderer{}
+
+	inspection := Inspect(renderer)
+
+	this.So(inspection.Renderers[0], should.Equal, renderer)
+	this.So(string(inspection.Body), should.Equal, "pointer")
+}
+
+func (this *InspectFixture) TestDefaults() {
+	inspection := Inspect(detour.NopRenderer{})
+
+	this.So(inspection.StatusCode, should.Equal, http.StatusOK)
+	this.So(inspection.Header, should.BeEmpty)
+	this.So(inspection.ContentType, should.BeBlank)
+	this.So(inspection.Body, should.BeEmpty)
+}
+
+func (this *InspectFixture) TestInspectRequest() {
+	request := httptest.NewRequest("GET", "/?callback=cb", nil)
+
+	inspection := InspectRequest(detour.JSONBodyRenderer{Content: 1, JSONp: true}, request)
+
+	this.So(string(inspection.Body), should.Equal, "/**/cb(1)")
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type PointerRenderer struct{}
+
+func (this *PointerRenderer) Render(response http.ResponseWriter, _ *http.Request) {
+	_, _ = response.Write([]byte("pointer"))
+}
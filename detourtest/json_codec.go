@@ -0,0 +1,193 @@
+package detourtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/smartystreets/detour"
+)
+
+// VerifyJSONCodec reports (via t.Errorf) each way in which the codec differs
+// from the output and behavior of detour.StandardJSONCodec (encoding/json).
+// Codecs provided to detour.SetJSONCodec or detour.UseJSONCodec should pass
+// so that swapping codecs doesn't change the responses clients receive:
+//
+//	func TestFastCodec(t *testing.T) {
+//		detourtest.VerifyJSONCodec(t, fast.Codec{})
+//	}
+func VerifyJSONCodec(t T, codec detour.JSONCodec) {
+	t.Helper()
+	verifier := codecVerifier{t: t, codec: codec, standard: detour.StandardJSONCodec{}}
+	for _, sample := range codecSamples() {
+		verifier.marshal(sample)
+		verifier.encode(sample)
+	}
+	verifier.unmarshal()
+	verifier.decode()
+	verifier.failures()
+}
+
+type codecVerifier struct {
+	t        T
+	codec    detour.JSONCodec
+	standard detour.JSONCodec
+}
+
+func (this codecVerifier) marshal(sample codecSample) {
+	this.t.Helper()
+	expected, _ := this.standard.Marshal(sample.value)
+	actual, err := this.codec.Marshal(sample.value)
+	if err != nil {
+		this.t.Errorf("Marshal(%s) failed: %s", sample.name, err)
+	} else if !bytes.Equal(actual, expected) {
+		this.t.Errorf("Marshal(%s)\nExpected: %s\nActual:   %s", sample.name, expected, actual)
+	}
+}
+
+func (this codecVerifier) encode(sample codecSample) {
+	this.t.Helper()
+	for _, options := range encoderOptions {
+		expected, _ := encodeWith(this.standard, options, sample.value)
+		actual, err := encodeWith(this.codec, options, sample.value)
+		if err != nil {
+			this.t.Errorf("Encode(%s) with %s failed: %s", sample.name, options, err)
+		} else if !bytes.Equal(actual, expected) {
+			this.t.Errorf("Encode(%s) with %s\nExpected: %q\nActual:   %q", sample.name, options, expected, actual)
+		}
+	}
+}
+
+func (this codecVerifier) unmarshal() {
+	this.t.Helper()
+	var expected, actual codecModel
+	_ = this.standard.Unmarshal([]byte(codecModelJSON), &expected)
+	if err := this.codec.Unmarshal([]byte(codecModelJSON), &actual); err != nil {
+		this.t.Errorf("Unmarshal failed: %s", err)
+	} else if !reflect.DeepEqual(actual, expected) {
+		this.t.Errorf("Unmarshal\nExpected: %+v\nActual:   %+v", expected, actual)
+	}
+
+	if err := this.codec.Unmarshal([]byte(invalidJSON), &actual); err == nil {
+		this.t.Errorf("Unmarshal of invalid JSON should fail: %s", invalidJSON)
+	}
+}
+
+func (this codecVerifier) decode() {
+	this.t.Helper()
+	var expected, actual codecModel
+	_ = this.standard.NewDecoder(strings.NewReader(codecModelJSON)).Decode(&expected)
+	if err := this.codec.NewDecoder(strings.NewReader(codecModelJSON)).Decode(&actual); err != nil {
+		this.t.Errorf("Decode failed: %s", err)
+	} else if !reflect.DeepEqual(actual, expected) {
+		this.t.Errorf("Decode\nExpected: %+v\nActual:   %+v", expected, actual)
+	}
+
+	if err := this.codec.NewDecoder(strings.NewReader(invalidJSON)).Decode(&actual); err == nil {
+		this.t.Errorf("Decode of invalid JSON should fail: %s", invalidJSON)
+	}
+}
+
+// failures verifies that marshal failures are reported (detour renders them as a 500).
+func (this codecVerifier) failures() {
+	this.t.Helper()
+	if _, err := this.codec.Marshal(failingMarshaler{}); err == nil {
+		this.t.Errorf("Marshal should report the error returned by MarshalJSON.")
+	}
+	if err := this.codec.NewEncoder(new(bytes.Buffer)).Encode(failingMarshaler{}); err == nil {
+		this.t.Errorf("Encode should report the error returned by MarshalJSON.")
+	}
+}
+
+type encoderOption struct {
+	escapeHTML bool
+	prefix     string
+	indent     string
+}
+
+func (this encoderOption) String() string {
+	return fmt.Sprintf("[escapeHTML:%t prefix:%q indent:%q]", this.escapeHTML, this.prefix, this.indent)
+}
+
+var encoderOptions = []encoderOption{
+	{escapeHTML: true},
+	{escapeHTML: false},
+	{escapeHTML: true, indent: "  "},
+	{escapeHTML: false, prefix: ">", indent: "\t"},
+}
+
+func encodeWith(codec detour.JSONCodec, options encoderOption, value interface{}) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	encoder := codec.NewEncoder(buffer)
+	encoder.SetEscapeHTML(options.escapeHTML)
+	encoder.SetIndent(options.prefix, options.indent)
+	err := encoder.Encode(value)
+	return buffer.Bytes(), err
+}
+
+type codecSample struct {
+	name  string
+	value interface{}
+}
+
+func codecSamples() []codecSample {
+	text := "pointer"
+	return []codecSample{
+		{name: "nil", value: nil},
+		{name: "string", value: "<script>&'\"   ünïcödé \x01"},
+		{name: "numbers", value: []interface{}{0, -1, 1.5, 1e21, 1e-7, uint64(1 << 63), float32(0.1)}},
+		{name: "map", value: map[string]interface{}{"b": true, "a": nil, "c": []int{}, "<": map[int]string{2: "x", 10: "y"}}},
+		{name: "struct", value: codecModel{
+			Name:    "<b>Name</b>",
+			Ignored: "ignored",
+			Bytes:   []byte("bytes"),
+			Time:    time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC),
+			Pointer: &text,
+			Raw:     json.RawMessage(`{"raw": [1, 2]}`),
+			Nested:  &codecModel{Name: "nested"},
+		}},
+		{name: "errors", value: detour.Errors{
+			detour.SimpleInputError("Required", "name"),
+			nil,
+			detour.CompoundInputError("Conflict", "start", "end"),
+		}},
+	}
+}
+
+type codecModel struct {
+	Name     string          `json:"name"`
+	Omitted  string          `json:"omitted,omitempty"`
+	Ignored  string          `json:"-"`
+	Number   int             `json:"number,string"`
+	Bytes    []byte          `json:"bytes"`
+	Time     time.Time       `json:"time"`
+	Pointer  *string         `json:"pointer"`
+	Raw      json.RawMessage `json:"raw"`
+	Nested   *codecModel     `json:"nested,omitempty"`
+	Untagged []int
+}
+
+const codecModelJSON = `{
+	"name": "<b>",
+	"ignored": "ignored",
+	"number": "42",
+	"bytes": "Ynl0ZXM=",
+	"time": "2020-01-02T03:04:05.000000006Z",
+	"pointer": null,
+	"raw": {"raw": [1, 2]},
+	"nested": {"name": "nested"},
+	"UNTAGGED": [1, 2, 3],
+	"unknown": {"ignored": true}
+}`
+
+const invalidJSON = `{"name": "unterminated`
+
+type failingMarshaler struct{}
+
+func (this failingMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("marshal failure")
+}
@@ -0,0 +1,51 @@
+package detourtest
+
+import (
+	"io"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/detour"
+	"github.com/smartystreets/gunit"
+)
+
+func TestJSONCodecFixture(t *testing.T) {
+	gunit.Run(new(JSONCodecFixture), t)
+}
+
+type JSONCodecFixture struct {
+	*gunit.Fixture
+
+	t *FakeT
+}
+
+func (this *JSONCodecFixture) Setup() {
+	this.t = new(FakeT)
+}
+
+func (this *JSONCodecFixture) TestStandardCodec_Conforms() {
+	VerifyJSONCodec(this.t, detour.StandardJSONCodec{})
+	this.So(this.t.failures, should.BeEmpty)
+}
+
+func (this *JSONCodecFixture) TestDeviatingCodec_FailuresReported() {
+	VerifyJSONCodec(this.t, AlwaysEscapingCodec{})
+	this.So(this.t.failures, should.NotBeEmpty)
+	for _, failure := range this.t.failures {
+		this.So(failure, should.StartWith, "Encode(")
+		this.So(failure, should.ContainSubstring, "escapeHTML:false")
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// AlwaysEscapingCodec ignores calls to SetEscapeHTML(false).
+type AlwaysEscapingCodec struct{ detour.StandardJSONCodec }
+
+func (this AlwaysEscapingCodec) NewEncoder(writer io.Writer) detour.JSONEncoder {
+	return alwaysEscapingEncoder{JSONEncoder: this.StandardJSONCodec.NewEncoder(writer)}
+}
+
+type alwaysEscapingEncoder struct{ detour.JSONEncoder }
+
+func (this alwaysEscapingEncoder) SetEscapeHTML(bool) {}
@@ -0,0 +1,126 @@
+package detourtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+)
+
+type RequestBuilder struct {
+	method  string
+	target  string
+	query   url.Values
+	form    url.Values
+	body    []byte
+	header  http.Header
+	values  []contextValue
+	context context.Context
+	err     error
+}
+
+type contextValue struct{ key, value interface{} }
+
+// NewRequest begins building a request for the method and target (a path with
+// an optional query string or an absolute URL, as accepted by httptest.NewRequest).
+func NewRequest(method, target string) *RequestBuilder {
+	return &RequestBuilder{
+		method: method,
+		target: target,
+		query:  make(url.Values),
+		form:   make(url.Values),
+		header: make(http.Header),
+	}
+}
+
+func (this *RequestBuilder) Query(key, value string) *RequestBuilder {
+	this.query.Add(key, value)
+	return this
+}
+
+// Form adds a value to a URL-encoded request body.
+func (this *RequestBuilder) Form(key, value string) *RequestBuilder {
+	this.form.Add(key, value)
+	return this
+}
+
+// JSON serializes the value as the request body and sets the Content-Type accordingly.
+func (this *RequestBuilder) JSON(value interface{}) *RequestBuilder {
+	this.body, this.err = json.Marshal(value)
+	return this.Header(contentTypeHeader, jsonContentType)
+}
+
+func (this *RequestBuilder) Body(body string) *RequestBuilder {
+	this.body = []byte(body)
+	return this
+}
+
+func (this *RequestBuilder) Header(key, value string) *RequestBuilder {
+	this.header.Add(key, value)
+	return this
+}
+
+// Context sets the context of the request, which is then decorated with any values (see Value).
+func (this *RequestBuilder) Context(ctx context.Context) *RequestBuilder {
+	this.context = ctx
+	return this
+}
+
+// Value adds a key/value pair to the context of the request.
+func (this *RequestBuilder) Value(key, value interface{}) *RequestBuilder {
+	this.values = append(this.values, contextValue{key: key, value: value})
+	return this
+}
+
+// Build assembles the request, panicking if the JSON body could not be serialized.
+func (this *RequestBuilder) Build() *http.Request {
+	if this.err != nil {
+		panic(this.err)
+	}
+
+	request := httptest.NewRequest(this.method, this.target, this.buildBody())
+	request.URL.RawQuery = this.buildQuery(request.URL.Query())
+	for key, values := range this.header {
+		request.Header[key] = append(request.Header[key], values...)
+	}
+	if len(this.form) > 0 && request.Header.Get(contentTypeHeader) == "" {
+		request.Header.Set(contentTypeHeader, formContentType)
+	}
+	return request.WithContext(this.buildContext(request.Context()))
+}
+func (this *RequestBuilder) buildBody() io.Reader {
+	if len(this.form) > 0 {
+		return strings.NewReader(this.form.Encode())
+	}
+	return bytes.NewReader(this.body)
+}
+func (this *RequestBuilder) buildQuery(query url.Values) string {
+	for key, values := range this.query {
+		query[key] = append(query[key], values...)
+	}
+	return query.Encode()
+}
+func (this *RequestBuilder) buildContext(ctx context.Context) context.Context {
+	if this.context != nil {
+		ctx = this.context
+	}
+	for _, pair := range this.values {
+		ctx = context.WithValue(ctx, pair.key, pair.value)
+	}
+	return ctx
+}
+
+// Serve builds the request and passes it to the handler, recording the response.
+func (this *RequestBuilder) Serve(handler http.Handler) *Response {
+	return Record(handler, this.Build())
+}
+
+const (
+	contentTypeHeader = "Content-Type"
+	jsonContentType   = "application/json"
+	formContentType   = "application/x-www-form-urlencoded"
+)
@@ -0,0 +1,89 @@
+package detourtest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestRequestBuilderFixture(t *testing.T) {
+	gunit.Run(new(RequestBuilderFixture), t)
+}
+
+type RequestBuilderFixture struct {
+	*gunit.Fixture
+}
+
+func (this *RequestBuilderFixture) readBody(request *http.Request) string {
+	body, _ := ioutil.ReadAll(request.Body)
+	return string(body)
+}
+
+func (this *RequestBuilderFixture) TestQueryAppendedToTargetQuery() {
+	request := NewRequest("GET", "/path?a=1").Query("b", "2").Query("a", "3").Build()
+
+	this.So(request.Method, should.Equal, "GET")
+	this.So(request.URL.Path, should.Equal, "/path")
+	this.So(request.URL.Query()["a"], should.Resemble, []string{"1", "3"})
+	this.So(request.URL.Query().Get("b"), should.Equal, "2")
+}
+
+func (this *RequestBuilderFixture) TestFormEncodedAsBody() {
+	request := NewRequest("POST", "/").Form("name", "Mike").Form("name", "Jo").Build()
+
+	this.So(request.Header.Get("Content-Type"), should.Equal, "application/x-www-form-urlencoded")
+	this.So(request.ParseForm(), should.BeNil)
+	this.So(request.PostForm["name"], should.Resemble, []string{"Mike", "Jo"})
+}
+
+func (this *RequestBuilderFixture) TestJSONBody() {
+	request := NewRequest("PUT", "/").JSON(map[string]int{"a": 1}).Build()
+
+	this.So(request.Header.Get("Content-Type"), should.Equal, "application/json")
+	this.So(this.readBody(request), should.Equal, `{"a":1}`)
+}
+
+func (this *RequestBuilderFixture) TestUnserializableJSON_BuildPanics() {
+	builder := NewRequest("PUT", "/").JSON(make(chan int))
+	this.So(func() { builder.Build() }, should.Panic)
+}
+
+func (this *RequestBuilderFixture) TestRawBodyAndHeaders() {
+	request := NewRequest("POST", "/").
+		Body("raw").
+		Header("Content-Type", "text/plain").
+		Header("x-multi", "1").
+		Header("x-multi", "2").
+		Build()
+
+	this.So(this.readBody(request), should.Equal, "raw")
+	this.So(request.Header.Get("Content-Type"), should.Equal, "text/plain")
+	this.So(request.Header["X-Multi"], should.Resemble, []string{"1", "2"})
+}
+
+func (this *RequestBuilderFixture) TestContextValues() {
+	parent := context.WithValue(context.Background(), "parent", "p")
+	request := NewRequest("GET", "/").Context(parent).Value("a", 1).Value("b", 2).Build()
+
+	this.So(request.Context().Value("parent"), should.Equal, "p")
+	this.So(request.Context().Value("a"), should.Equal, 1)
+	this.So(request.Context().Value("b"), should.Equal, 2)
+}
+
+func (this *RequestBuilderFixture) TestServeRecordsResponse() {
+	handler := http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		response.Header().Set("X-Method", request.Method)
+		response.WriteHeader(http.StatusTeapot)
+		_, _ = response.Write([]byte(request.URL.Query().Get("q")))
+	})
+
+	response := NewRequest("DELETE", "/").Query("q", "hi").Serve(handler)
+
+	this.So(response.StatusCode(), should.Equal, http.StatusTeapot)
+	this.So(response.Header().Get("X-Method"), should.Equal, "DELETE")
+	this.So(response.Body(), should.Equal, "hi")
+}
@@ -0,0 +1,140 @@
+package detourtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+
+	"github.com/smartystreets/detour"
+)
+
+// Response is a recorded response, as returned by RequestBuilder.Serve.
+// Each Assert method reports failures via t.Errorf and returns the
+// response so that assertions may be chained.
+type Response struct {
+	recorder *httptest.ResponseRecorder
+}
+
+// Record passes the request to the handler, recording the response.
+func Record(handler http.Handler, request *http.Request) *Response {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return &Response{recorder: recorder}
+}
+
+func (this *Response) StatusCode() int     { return this.recorder.Code }
+func (this *Response) Header() http.Header { return this.recorder.Result().Header }
+func (this *Response) Body() string        { return this.recorder.Body.String() }
+
+// DecodeJSON deserializes the response body into the target.
+func (this *Response) DecodeJSON(target interface{}) error {
+	return json.Unmarshal(this.recorder.Body.Bytes(), target)
+}
+
+// Errors decodes a detour.Errors response body.
+func (this *Response) Errors() ([]detour.InputError, error) {
+	return DecodeErrors(this.recorder.Body.Bytes())
+}
+
+func (this *Response) AssertStatus(t T, expected int) *Response {
+	t.Helper()
+	if actual := this.StatusCode(); actual != expected {
+		t.Errorf("Expected status code: [%d] Actual: [%d] (body: %q)", expected, actual, this.Body())
+	}
+	return this
+}
+
+// AssertHeader verifies that the response header with the key contains the value.
+func (this *Response) AssertHeader(t T, key, expected string) *Response {
+	t.Helper()
+	values := this.Header()[http.CanonicalHeaderKey(key)]
+	for _, value := range values {
+		if value == expected {
+			return this
+		}
+	}
+	t.Errorf("Expected header [%s] to contain: [%s] Actual: %q", key, expected, values)
+	return this
+}
+
+func (this *Response) AssertNoHeader(t T, key string) *Response {
+	t.Helper()
+	if values, found := this.Header()[http.CanonicalHeaderKey(key)]; found {
+		t.Errorf("Expected no [%s] header. Actual: %q", key, values)
+	}
+	return this
+}
+
+func (this *Response) AssertBody(t T, expected string) *Response {
+	t.Helper()
+	if actual := this.Body(); actual != expected {
+		t.Errorf("Expected body: %q Actual: %q", expected, actual)
+	}
+	return this
+}
+
+// AssertJSON verifies that the response body is JSON which is semantically equal
+// to the expected value, which may be raw JSON (string or []byte) or any value
+// that can be serialized as JSON. Formatting and key order are irrelevant.
+func (this *Response) AssertJSON(t T, expected interface{}) *Response {
+	t.Helper()
+	var actualValue interface{}
+	if err := this.DecodeJSON(&actualValue); err != nil {
+		t.Errorf("Expected a JSON body. Decoding failed: [%s] (body: %q)", err, this.Body())
+		return this
+	}
+	expectedValue, err := normalizeJSON(expected)
+	if err != nil {
+		t.Errorf("Could not interpret expected value as JSON: [%s]", err)
+		return this
+	}
+	if !reflect.DeepEqual(expectedValue, actualValue) {
+		t.Errorf("Expected JSON: %s Actual: %s", describeJSON(expectedValue), this.Body())
+	}
+	return this
+}
+
+// AssertErrors verifies that the response body is a detour.Errors value
+// containing exactly the expected input errors (in order).
+func (this *Response) AssertErrors(t T, expected ...detour.InputError) *Response {
+	t.Helper()
+	actual, err := this.Errors()
+	if err != nil {
+		t.Errorf("Expected an errors body. Decoding failed: [%s] (body: %q)", err, this.Body())
+	} else if !equalInputErrors(expected, actual) {
+		t.Errorf("Expected errors: %s Actual: %s", describeJSON(expected), describeJSON(actual))
+	}
+	return this
+}
+
+func normalizeJSON(value interface{}) (normalized interface{}, err error) {
+	var raw []byte
+	switch typed := value.(type) {
+	case string:
+		raw = []byte(typed)
+	case []byte:
+		raw = typed
+	default:
+		if raw, err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+	err = json.Unmarshal(raw, &normalized)
+	return normalized, err
+}
+
+func describeJSON(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(raw)
+}
+
+// T is the subset of testing.TB used by the assertion helpers.
+type T interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
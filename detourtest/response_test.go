@@ -0,0 +1,126 @@
+package detourtest
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/detour"
+	"github.com/smartystreets/gunit"
+)
+
+func TestResponseFixture(t *testing.T) {
+	gunit.Run(new(ResponseFixture), t)
+}
+
+type ResponseFixture struct {
+	*gunit.Fixture
+
+	t *FakeT
+}
+
+func (this *ResponseFixture) Setup() {
+	this.t = new(FakeT)
+}
+
+func (this *ResponseFixture) serve(renderer detour.Renderer) *Response {
+	return NewRequest("GET", "/").Serve(detour.New(func() detour.Renderer { return renderer }))
+}
+
+func (this *ResponseFixture) TestPassingAssertions() {
+	response := this.serve(detour.JSONResult{
+		StatusCode: http.StatusCreated,
+		Content:    map[string]interface{}{"b": []int{1, 2}, "a": "x"},
+		Header:     http.Header{"X-Id": {"42"}},
+	})
+
+	response.
+		AssertStatus(this.t, http.StatusCreated).
+		AssertHeader(this.t, "x-id", "42").
+		AssertHeader(this.t, "Content-Type", "application/json; charset=utf-8").
+		AssertNoHeader(this.t, "Location").
+		AssertBody(this.t, `{"a":"x","b":[1,2]}`+"\n").
+		AssertJSON(this.t, `{"b": [1, 2], "a": "x"}`).
+		AssertJSON(this.t, map[string]interface{}{"a": "x", "b": []int{1, 2}})
+
+	this.So(this.t.failures, should.BeEmpty)
+}
+
+func (this *ResponseFixture) TestFailingAssertions() {
+	response := this.serve(detour.StatusCodeResult{StatusCode: http.StatusTeapot, Message: "nope"})
+
+	response.
+		AssertStatus(this.t, http.StatusOK).
+		AssertHeader(this.t, "X-Missing", "value").
+		AssertNoHeader(this.t, "Content-Type").
+		AssertBody(this.t, "yep").
+		AssertJSON(this.t, `{}`)
+
+	this.So(this.t.failures, should.Resemble, []string{
+		`Expected status code: [200] Actual: [418] (body: "nope")`,
+		`Expected header [X-Missing] to contain: [value] Actual: []`,
+		`Expected no [Content-Type] header. Actual: ["text/plain; charset=utf-8"]`,
+		`Expected body: "yep" Actual: "nope"`,
+		`Expected a JSON body. Decoding failed: [invalid character 'o' in literal null (expecting 'u')] (body: "nope")`,
+	})
+}
+
+func (this *ResponseFixture) TestAssertJSON_Mismatch() {
+	this.serve(detour.JSONResult{Content: []int{1}}).AssertJSON(this.t, []int{2})
+
+	this.So(this.t.failures, should.Resemble, []string{"Expected JSON: [2] Actual: [1]\n"})
+}
+
+func (this *ResponseFixture) TestDecodeJSON() {
+	var decoded []int
+	err := this.serve(detour.JSONResult{Content: []int{1, 2}}).DecodeJSON(&decoded)
+
+	this.So(err, should.BeNil)
+	this.So(decoded, should.Resemble, []int{1, 2})
+}
+
+func (this *ResponseFixture) TestErrors() {
+	response := this.serve(detour.ValidationResult{
+		Failure1: detour.SimpleInputError("required", "name"),
+		Failure2: detour.CompoundInputError("mismatch", "a", "b"),
+	})
+
+	errs, err := response.Errors()
+
+	this.So(err, should.BeNil)
+	this.So(errs, should.Resemble, []detour.InputError{
+		{Fields: []string{"name"}, Message: "required"},
+		{Fields: []string{"a", "b"}, Message: "mismatch"},
+	})
+	response.AssertErrors(this.t,
+		detour.InputError{Fields: []string{"name"}, Message: "required"},
+		detour.InputError{Fields: []string{"a", "b"}, Message: "mismatch"})
+	this.So(this.t.failures, should.BeEmpty)
+}
+
+func (this *ResponseFixture) TestAssertErrors_Mismatch() {
+	response := this.serve(detour.ValidationResult{Failure1: detour.SimpleInputError("required", "name")})
+
+	response.AssertErrors(this.t, detour.InputError{Fields: []string{"other"}, Message: "required"})
+
+	this.So(this.t.failures, should.Resemble, []string{
+		`Expected errors: [{"fields":["other"],"message":"required"}] Actual: [{"fields":["name"],"message":"required"}]`,
+	})
+}
+
+func (this *ResponseFixture) TestDecodeErrors_NullFieldsEqualEmptyFields() {
+	errs, err := DecodeErrors([]byte(`[{"fields":null,"message":"oops"}]`))
+
+	this.So(err, should.BeNil)
+	this.So(equalInputErrors(errs, []detour.InputError{{Fields: []string{}, Message: "oops"}}), should.BeTrue)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type FakeT struct{ failures []string }
+
+func (this *FakeT) Helper() {}
+func (this *FakeT) Errorf(format string, args ...interface{}) {
+	this.failures = append(this.failures, fmt.Sprintf(format, args...))
+}
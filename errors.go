@@ -1,7 +1,5 @@
 package detour
 
-import "encoding/json"
-
 type Errors []error
 
 func (this Errors) AppendIf(err error, condition bool) Errors {
@@ -19,6 +17,13 @@ func (this Errors) Append(err error) Errors {
 	return this
 }
 
+func appendAll(failures Errors, errs []error) Errors {
+	for _, err := range errs {
+		failures = failures.Append(err)
+	}
+	return failures
+}
+
 func (this Errors) Error() string {
 	raw, _ := this.MarshalJSON()
 	return string(raw)
@@ -31,7 +36,7 @@ func (this Errors) MarshalJSON() ([]byte, error) {
 			filtered = append(filtered, err)
 		}
 	}
-	return json.Marshal(filtered)
+	return defaultSettings.codec().Marshal(filtered)
 }
 
 func (this Errors) StatusCode() int {
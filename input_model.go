@@ -1,28 +1,36 @@
 package detour
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 	"strings"
 )
 
 func prepareInputModel(model interface{}, request *http.Request) (statusCode int, err error) {
-	if err = Bind(request, model); err != nil {
-		return statusCodeFromErrorOrDefault(err, http.StatusBadRequest)
+	preparation := Prepare(request, model)
+	return preparation.StatusCode, preparation.Error
+}
+
+// Prepare is exported for use in testing. It runs the exact pipeline applied to
+// each input model before the controller action is called: Bind (including JSON
+// and context binding), Sanitize, Validate, and the ServerError check, stopping
+// at the first stage that fails.
+func Prepare(request *http.Request, model interface{}) Preparation {
+	if err := Bind(request, model); err != nil {
+		return newPreparation(StageBind, err, http.StatusBadRequest)
 	}
 
 	sanitize(model)
 
-	if err = validate(model); err != nil {
-		return statusCodeFromErrorOrDefault(err, http.StatusUnprocessableEntity)
+	if err := validate(model); err != nil {
+		return newPreparation(StageValidate, err, http.StatusUnprocessableEntity)
 	}
 
-	if err = serverError(model); err != nil {
-		return http.StatusInternalServerError, err
+	if err := serverError(model); err != nil {
+		return Preparation{Stage: StageServerError, StatusCode: http.StatusInternalServerError, Error: err}
 	}
 
-	return 0, nil
+	return Preparation{}
 }
 
 // Bind is exported for use in testing.
@@ -36,6 +44,11 @@ func Bind(request *http.Request, message interface{}) error {
 		return err
 	}
 
+	err = bindCookies(request, message)
+	if err != nil {
+		return err
+	}
+
 	binder, isBinder := message.(Binder)
 	if !isBinder {
 		return nil
@@ -79,7 +92,7 @@ func bindJSON(request *http.Request, message interface{}) error {
 		return errUnsupportedMediaType
 	}
 
-	return json.NewDecoder(request.Body).Decode(&message)
+	return currentSettings(request).codec().NewDecoder(request.Body).Decode(message)
 }
 func isPutOrPost(request *http.Request) bool {
 	return request.Method == http.MethodPost || request.Method == http.MethodPut
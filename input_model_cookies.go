@@ -0,0 +1,113 @@
+package detour
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// bindCookies verifies and decodes the cookies named by `bind:"cookie:name"` tags
+// into the (string or encoding.TextUnmarshaler) fields of a BindCookies model.
+// Missing cookies are skipped; invalid (ie. tampered) cookies result in a 400.
+func bindCookies(request *http.Request, message interface{}) error {
+	binder, ok := message.(BindCookies)
+	if !ok {
+		return nil
+	}
+	codec := binder.CookieCodec()
+	if codec == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(message)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs Errors
+	bindCookieFields(request, codec, value, &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func bindCookieFields(request *http.Request, codec *CookieCodec, model reflect.Value, errs *Errors) {
+	modelType := model.Type()
+	for x := 0; x < modelType.NumField(); x++ {
+		field := modelType.Field(x)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			bindCookieFields(request, codec, model.Field(x), errs)
+			continue
+		}
+
+		source, name := splitPair(field.Tag.Get("bind"), ":")
+		if source != bindCookie || !isCookieField(field) {
+			continue // unsupported fields are reported when the handler is built (see verifyCookieFields)
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+
+		decoded, err := codec.Cookie(request, name)
+		if err == http.ErrNoCookie {
+			continue
+		} else if err != nil {
+			*errs = errs.Append(err)
+		} else if err = assignCookieField(model.Field(x), decoded); err != nil {
+			*errs = errs.Append(invalidCookieError(name))
+		}
+	}
+}
+
+func assignCookieField(field reflect.Value, decoded string) error {
+	if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(decoded))
+	}
+	field.SetString(decoded)
+	return nil
+}
+
+// verifyCookieFields panics unless every field of a BindCookies model tagged
+// `bind:"cookie"` can be bound (ie. is an exported string or encoding.TextUnmarshaler).
+func verifyCookieFields(modelType reflect.Type) {
+	if modelType == nil || !modelType.Implements(bindCookiesType) {
+		return
+	}
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType.Kind() == reflect.Struct {
+		verifyCookieStructFields(modelType)
+	}
+}
+func verifyCookieStructFields(structType reflect.Type) {
+	for x := 0; x < structType.NumField(); x++ {
+		field := structType.Field(x)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			verifyCookieStructFields(field.Type)
+			continue
+		}
+		if source, _ := splitPair(field.Tag.Get("bind"), ":"); source == bindCookie && !isCookieField(field) {
+			panic(fmt.Sprintf("Cookies can only be bound to exported string or encoding.TextUnmarshaler fields, not: [%v.%s %v]",
+				structType, field.Name, field.Type))
+		}
+	}
+}
+func isCookieField(field reflect.StructField) bool {
+	if len(field.PkgPath) > 0 {
+		return false // unexported
+	}
+	return field.Type.Kind() == reflect.String || reflect.PtrTo(field.Type).Implements(textUnmarshalerType)
+}
+
+var (
+	bindCookiesType     = reflect.TypeOf((*BindCookies)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+const bindCookie = "cookie"
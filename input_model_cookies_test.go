@@ -0,0 +1,131 @@
+package detour
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestCookieBindingFixture(t *testing.T) {
+	gunit.Run(new(CookieBindingFixture), t)
+}
+
+type CookieBindingFixture struct {
+	*gunit.Fixture
+
+	request  *http.Request
+	response *httptest.ResponseRecorder
+}
+
+func (this *CookieBindingFixture) Setup() {
+	this.request = httptest.NewRequest("GET", "/", nil)
+	this.response = httptest.NewRecorder()
+}
+
+func (this *CookieBindingFixture) addCookie(name, value string) {
+	encoded, _ := cookieBindingCodec.Encode(name, value)
+	this.request.AddCookie(&http.Cookie{Name: name, Value: encoded})
+}
+
+func (this *CookieBindingFixture) TestCookiesDecodedIntoFields() {
+	this.addCookie("session", "user-42")
+	this.addCookie("Theme", "dark")
+	model := &CookieBindingInputModel{}
+
+	err := Bind(this.request, model)
+
+	this.So(err, should.BeNil)
+	this.So(model.Session, should.Equal, "user-42")
+	this.So(model.Theme, should.Equal, upperText("DARK"))
+	this.So(model.Embedded, should.BeBlank)
+	this.So(model.Unprotected, should.BeBlank)
+}
+
+func (this *CookieBindingFixture) TestTamperedCookie_HTTP400() {
+	this.addCookie("session", "user-42")
+	this.request.AddCookie(&http.Cookie{Name: "embedded", Value: "forged.value"})
+
+	New(func(model *CookieBindingInputModel) Renderer {
+		panic("We shouldn't reach this point because the binding failed.")
+	}).ServeHTTP(this.response, this.request)
+
+	this.So(this.response.Code, should.Equal, http.StatusBadRequest)
+	this.So(this.response.Body.String(), should.EqualTrimSpace, `[{"fields":["embedded"],"message":"Invalid cookie"}]`)
+}
+
+func (this *CookieBindingFixture) TestNilCodec_NothingBound() {
+	this.addCookie("session", "user-42")
+	model := &CookieBindingWithoutCodec{}
+
+	this.So(Bind(this.request, model), should.BeNil)
+	this.So(model.Session, should.BeBlank)
+}
+
+func (this *CookieBindingFixture) TestUnsupportedFields_PanicWhenHandlerBuilt() {
+	this.So(func() { New(func(*CookieBindingUnsupportedField) Renderer { return nil }) }, should.Panic)
+	this.So(func() { New(func(*CookieBindingUnexportedField) Renderer { return nil }) }, should.Panic)
+	this.So(func() {
+		NewFromFactory(func() interface{} { return new(CookieBindingUnsupportedField) },
+			func(*CookieBindingUnsupportedField) Renderer { return nil })
+	}, should.Panic)
+	this.So(func() { New(func(*CookieBindingInputModel) Renderer { return nil }) }, should.NotPanic)
+}
+
+func (this *CookieBindingFixture) TestUnsupportedFields_SkippedWhenBinding() {
+	this.addCookie("count", "1")
+	unsupported := &CookieBindingUnsupportedField{}
+	unexported := &CookieBindingUnexportedField{}
+
+	this.So(Bind(this.request, unsupported), should.BeNil)
+	this.So(Bind(this.request, unexported), should.BeNil)
+
+	this.So(unsupported.Count, should.Equal, 0)
+	this.So(unexported.count, should.BeBlank)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var cookieBindingCodec, _ = NewCookieCodec(CookieKey{Signing: []byte("binding-key")})
+
+type CookieBindingInputModel struct {
+	EmbeddedCookies
+
+	Session     string    `bind:"cookie:session"`
+	Theme       upperText `bind:"cookie"`
+	Unprotected string
+}
+
+type EmbeddedCookies struct {
+	Embedded string `bind:"cookie:embedded"`
+}
+
+func (this *CookieBindingInputModel) CookieCodec() *CookieCodec { return cookieBindingCodec }
+
+type upperText string
+
+func (this *upperText) UnmarshalText(text []byte) error {
+	*this = upperText(strings.ToUpper(string(text)))
+	return nil
+}
+
+type CookieBindingWithoutCodec struct {
+	Session string `bind:"cookie:session"`
+}
+
+func (this *CookieBindingWithoutCodec) CookieCodec() *CookieCodec { return nil }
+
+type CookieBindingUnsupportedField struct {
+	Count int `bind:"cookie:count"`
+}
+
+func (this *CookieBindingUnsupportedField) CookieCodec() *CookieCodec { return cookieBindingCodec }
+
+type CookieBindingUnexportedField struct {
+	count string `bind:"cookie:count"`
+}
+
+func (this *CookieBindingUnexportedField) CookieCodec() *CookieCodec { return cookieBindingCodec }
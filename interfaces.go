@@ -18,6 +18,15 @@ type (
 		BindJSON() bool
 	}
 
+	BindCookies interface {
+		CookieCodec() *CookieCodec
+	}
+
+	// Methods restricts the HTTP methods with which the model may be requested (see restrictMethods).
+	Methods interface {
+		Methods() []string
+	}
+
 	Sanitizer interface {
 		Sanitize()
 	}
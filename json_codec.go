@@ -0,0 +1,65 @@
+package detour
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONCodec allows a third-party JSON implementation to be used in place of
+// encoding/json wherever detour binds or renders JSON. Implementations should
+// pass detourtest.VerifyJSONCodec.
+type JSONCodec interface {
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(data []byte, value interface{}) error
+	NewEncoder(writer io.Writer) JSONEncoder
+	NewDecoder(reader io.Reader) JSONDecoder
+}
+
+// JSONEncoder is satisfied by *json.Encoder.
+type JSONEncoder interface {
+	Encode(value interface{}) error
+	SetEscapeHTML(on bool)
+	SetIndent(prefix, indent string)
+}
+
+// JSONDecoder is satisfied by *json.Decoder.
+type JSONDecoder interface {
+	Decode(value interface{}) error
+}
+
+// StandardJSONCodec is the default JSONCodec, backed by encoding/json.
+type StandardJSONCodec struct{}
+
+func (this StandardJSONCodec) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+func (this StandardJSONCodec) Unmarshal(data []byte, value interface{}) error {
+	return json.Unmarshal(data, value)
+}
+func (this StandardJSONCodec) NewEncoder(writer io.Writer) JSONEncoder {
+	return json.NewEncoder(writer)
+}
+func (this StandardJSONCodec) NewDecoder(reader io.Reader) JSONDecoder {
+	return json.NewDecoder(reader)
+}
+
+// SetJSONCodec sets the default for all handlers. It should only be called during initialization.
+// Values that detour serializes without reference to any handler (such as Errors.MarshalJSON)
+// always use the default.
+func SetJSONCodec(codec JSONCodec) {
+	defaultSettings.jsonCodec = codec
+}
+
+// UseJSONCodec overrides the default established by SetJSONCodec for a single handler.
+func UseJSONCodec(codec JSONCodec) Option {
+	return func(this *actionHandler) {
+		this.settings = append(this.settings, func(settings *settings) { settings.jsonCodec = codec })
+	}
+}
+
+func (this settings) codec() JSONCodec {
+	if this.jsonCodec == nil {
+		return StandardJSONCodec{}
+	}
+	return this.jsonCodec
+}
@@ -0,0 +1,100 @@
+package detour_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/detour"
+	"github.com/smartystreets/detour/detourtest"
+	"github.com/smartystreets/gunit"
+)
+
+func TestStandardJSONCodecConformance(t *testing.T) {
+	detourtest.VerifyJSONCodec(t, detour.StandardJSONCodec{})
+}
+
+func TestJSONCodecFixture(t *testing.T) {
+	gunit.Run(new(JSONCodecFixture), t)
+}
+
+type JSONCodecFixture struct {
+	*gunit.Fixture
+
+	codec *RecordingCodec
+}
+
+func (this *JSONCodecFixture) Setup() {
+	this.codec = new(RecordingCodec)
+}
+
+func (this *JSONCodecFixture) TestHandlerCodec_UsedToBindAndRender() {
+	handler := detour.New(func(model *CodecModel) detour.Renderer {
+		return detour.JSONResult{Content: model}
+	}, detour.UseJSONCodec(this.codec))
+
+	response := detourtest.NewRequest("PUT", "/").JSON(CodecModel{Name: "<Gopher>"}).Serve(handler)
+
+	this.So(response.StatusCode(), should.Equal, http.StatusOK)
+	this.So(response.Body(), should.Equal, `{"name":"\u003cGopher\u003e"}`+"\n")
+
+	this.So(this.codec.decoders, should.Equal, 1)
+	this.So(this.codec.encoders, should.Equal, 1)
+}
+
+func (this *JSONCodecFixture) TestHandlerCodec_DecodesIntoModelPointer() {
+	handler := detour.New(func(model *CodecModel) detour.Renderer { return nil }, detour.UseJSONCodec(this.codec))
+
+	detourtest.NewRequest("PUT", "/").JSON(CodecModel{Name: "Gopher"}).Serve(handler)
+
+	this.So(this.codec.targets, should.Resemble, []interface{}{&CodecModel{Name: "Gopher"}})
+}
+
+func (this *JSONCodecFixture) TestDefaultCodec_UsedWithoutHandlerOverride() {
+	detour.SetJSONCodec(this.codec)
+	defer detour.SetJSONCodec(nil)
+	handler := detour.New(func() detour.Renderer { return detour.JSONResult{Content: 1} })
+
+	response := detourtest.NewRequest("GET", "/").Serve(handler)
+
+	this.So(response.Body(), should.Equal, "1\n")
+
+	this.So(this.codec.encoders, should.Equal, 1)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type CodecModel struct {
+	Name string `json:"name"`
+}
+
+func (this *CodecModel) BindJSON() bool { return true }
+
+type RecordingCodec struct {
+	detour.StandardJSONCodec
+
+	encoders int
+	decoders int
+	targets  []interface{}
+}
+
+func (this *RecordingCodec) NewEncoder(writer io.Writer) detour.JSONEncoder {
+	this.encoders++
+	return this.StandardJSONCodec.NewEncoder(writer)
+}
+func (this *RecordingCodec) NewDecoder(reader io.Reader) detour.JSONDecoder {
+	this.decoders++
+	return &RecordingDecoder{JSONDecoder: this.StandardJSONCodec.NewDecoder(reader), codec: this}
+}
+
+type RecordingDecoder struct {
+	detour.JSONDecoder
+
+	codec *RecordingCodec
+}
+
+func (this *RecordingDecoder) Decode(target interface{}) error {
+	this.codec.targets = append(this.codec.targets, target)
+	return this.JSONDecoder.Decode(target)
+}
@@ -0,0 +1,43 @@
+package detour
+
+import "bytes"
+
+// JSONEncoding governs how JSONResult, JSONPResult, and JSONBodyRenderer serialize their Content.
+// The zero value matches the behavior of a default json.Encoder.
+type JSONEncoding struct {
+	DisableHTMLEscaping bool   // see json.Encoder.SetEscapeHTML
+	Prefix              string // see json.Encoder.SetIndent
+	Indent              string // used unless the renderer specifies its own Indent
+	OmitTrailingNewline bool   // json.Encoder terminates each value with a newline (but see JSONPResult and JSONBodyRenderer)
+}
+
+// SetJSONEncoding sets the default for all handlers. It should only be called during initialization.
+func SetJSONEncoding(encoding JSONEncoding) {
+	defaultSettings.jsonEncoding = encoding
+}
+
+// EncodeJSON overrides the default established by SetJSONEncoding for a single handler.
+func EncodeJSON(encoding JSONEncoding) Option {
+	return func(this *actionHandler) {
+		this.settings = append(this.settings, func(settings *settings) { settings.jsonEncoding = encoding })
+	}
+}
+
+func (this settings) encodeJSON(content interface{}, indent string) ([]byte, error) {
+	return this.jsonEncoding.encode(this.codec(), content, indent)
+}
+
+func (this JSONEncoding) encode(codec JSONCodec, content interface{}, indent string) ([]byte, error) {
+	writer := new(bytes.Buffer)
+	encoder := codec.NewEncoder(writer)
+	encoder.SetEscapeHTML(!this.DisableHTMLEscaping)
+	encoder.SetIndent(this.Prefix, firstNonBlank(indent, this.Indent))
+	if err := encoder.Encode(content); err != nil {
+		return nil, err
+	}
+	serialized := writer.Bytes()
+	if this.OmitTrailingNewline {
+		serialized = bytes.TrimSuffix(serialized, []byte("\n"))
+	}
+	return serialized, nil
+}
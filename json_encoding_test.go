@@ -0,0 +1,72 @@
+package detour
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestJSONEncodingFixture(t *testing.T) {
+	gunit.Run(new(JSONEncodingFixture), t)
+}
+
+type JSONEncodingFixture struct {
+	*gunit.Fixture
+}
+
+func (this *JSONEncodingFixture) encode(encoding JSONEncoding, content interface{}, indent string) string {
+	serialized, err := encoding.encode(StandardJSONCodec{}, content, indent)
+	this.So(err, should.BeNil)
+	return string(serialized)
+}
+
+func (this *JSONEncodingFixture) TestDefaults_MatchJSONEncoder() {
+	this.So(this.encode(JSONEncoding{}, []string{"<&>"}, ""), should.Equal, `["\u003c\u0026\u003e"]`+"\n")
+}
+
+func (this *JSONEncodingFixture) TestDisableHTMLEscaping() {
+	this.So(this.encode(JSONEncoding{DisableHTMLEscaping: true}, []string{"<&>"}, ""), should.Equal, `["<&>"]`+"\n")
+}
+
+func (this *JSONEncodingFixture) TestOmitTrailingNewline() {
+	this.So(this.encode(JSONEncoding{OmitTrailingNewline: true}, 1, ""), should.Equal, "1")
+}
+
+func (this *JSONEncodingFixture) TestPrefixAndIndent() {
+	this.So(this.encode(JSONEncoding{Prefix: ">", Indent: "\t"}, []int{1}, ""), should.Equal, "[\n>\t1\n>]\n")
+}
+
+func (this *JSONEncodingFixture) TestRendererIndent_TakesPrecedence() {
+	this.So(this.encode(JSONEncoding{Indent: "\t"}, []int{1}, "  "), should.Equal, "[\n  1\n]\n")
+}
+
+func (this *JSONEncodingFixture) TestSerializationFailure() {
+	serialized, err := JSONEncoding{}.encode(StandardJSONCodec{}, new(BadJSON), "")
+	this.So(serialized, should.BeNil)
+	this.So(err, should.NotBeNil)
+}
+
+func (this *JSONEncodingFixture) TestDefaultEncoding_UsedByRenderers() {
+	SetJSONEncoding(JSONEncoding{OmitTrailingNewline: true})
+	defer SetJSONEncoding(JSONEncoding{})
+	response := httptest.NewRecorder()
+
+	JSONResult{Content: 1}.Render(response, httptest.NewRequest("GET", "/", nil))
+
+	this.So(response.Body.String(), should.Equal, "1")
+}
+
+func (this *JSONEncodingFixture) TestHandlerEncoding_OverridesDefault() {
+	SetJSONEncoding(JSONEncoding{OmitTrailingNewline: true})
+	defer SetJSONEncoding(JSONEncoding{})
+	handler := New(func() Renderer { return JSONResult{Content: "<b>"} }, EncodeJSON(JSONEncoding{DisableHTMLEscaping: true}))
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil))
+
+	this.So(response.Code, should.Equal, http.StatusOK)
+	this.So(response.Body.String(), should.Equal, `"<b>"`+"\n")
+}
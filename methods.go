@@ -0,0 +1,59 @@
+package detour
+
+import (
+	"net/http"
+	"strings"
+)
+
+// restrictMethods provides the response to requests for models implementing Methods
+// that either use the OPTIONS method (204, with the Allow header) or a method other
+// than those allowed (405, with the Allow header). Otherwise it returns nil. Allowing
+// GET implicitly allows HEAD, and OPTIONS is always allowed.
+func restrictMethods(model interface{}, request *http.Request) Renderer {
+	restricted, ok := model.(Methods)
+	if !ok {
+		return nil
+	}
+
+	allowed := allowedMethods(restricted.Methods())
+	if request.Method == http.MethodOptions {
+		return CompoundRenderer{allowHeader(allowed), StatusCodeRenderer(http.StatusNoContent)}
+	}
+	for _, method := range allowed {
+		if method == request.Method {
+			return nil
+		}
+	}
+	return CompoundRenderer{allowHeader(allowed), &StatusCodeResult{
+		StatusCode: http.StatusMethodNotAllowed,
+		Message:    http.StatusText(http.StatusMethodNotAllowed),
+	}}
+}
+
+func allowedMethods(methods []string) (allowed []string) {
+	include := func(method string) {
+		for _, existing := range allowed {
+			if existing == method {
+				return
+			}
+		}
+		allowed = append(allowed, method)
+	}
+	for _, method := range methods {
+		method = strings.ToUpper(method)
+		include(method)
+		if method == http.MethodGet {
+			include(http.MethodHead)
+		}
+	}
+	include(http.MethodOptions)
+	return allowed
+}
+
+func allowHeader(methods []string) Renderer {
+	return SetHeaderPairsRenderer{allowHeaderName, strings.Join(methods, ", ")}
+}
+
+var jsonMethods = []string{http.MethodPost, http.MethodPut}
+
+const allowHeaderName = "Allow"
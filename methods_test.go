@@ -0,0 +1,96 @@
+package detour
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestMethodsFixture(t *testing.T) {
+	gunit.Run(new(MethodsFixture), t)
+}
+
+type MethodsFixture struct {
+	*gunit.Fixture
+
+	handler  http.Handler
+	response *httptest.ResponseRecorder
+}
+
+func (this *MethodsFixture) Setup() {
+	this.handler = New(func(model *MethodRestrictedModel) Renderer {
+		return StringBodyRenderer("bound: " + model.Value)
+	})
+	this.response = httptest.NewRecorder()
+}
+
+func (this *MethodsFixture) serve(method string) {
+	this.handler.ServeHTTP(this.response, httptest.NewRequest(method, "/?value=x", nil))
+}
+
+func (this *MethodsFixture) TestAllowedMethod_Handled() {
+	this.serve("PATCH")
+
+	this.So(this.response.Code, should.Equal, http.StatusOK)
+	this.So(this.response.Body.String(), should.Equal, "bound: x")
+	this.So(this.response.Header().Get(allowHeaderName), should.BeBlank)
+}
+
+func (this *MethodsFixture) TestHEAD_AllowedWithGET() {
+	this.serve("HEAD")
+
+	this.So(this.response.Code, should.Equal, http.StatusOK)
+	this.So(this.response.Body.Len(), should.Equal, 0)
+}
+
+func (this *MethodsFixture) TestOtherMethod_MethodNotAllowedBeforeBinding() {
+	this.serve("DELETE")
+
+	this.So(this.response.Code, should.Equal, http.StatusMethodNotAllowed)
+	this.So(this.response.Header().Get(allowHeaderName), should.Equal, "GET, HEAD, PATCH, OPTIONS")
+	this.So(this.response.Body.String(), should.Equal, "Method Not Allowed")
+}
+
+func (this *MethodsFixture) TestOPTIONS_AnsweredAutomatically() {
+	this.serve("OPTIONS")
+
+	this.So(this.response.Code, should.Equal, http.StatusNoContent)
+	this.So(this.response.Header().Get(allowHeaderName), should.Equal, "GET, HEAD, PATCH, OPTIONS")
+	this.So(this.response.Body.Len(), should.Equal, 0)
+}
+
+func (this *MethodsFixture) TestOPTIONS_CORSPreflightTakesPrecedence() {
+	this.handler = New(func(*MethodRestrictedModel) Renderer { return nil },
+		CORS(CORSPolicy{AllowedOrigins: []string{"*"}}))
+	request := httptest.NewRequest("OPTIONS", "/", nil)
+	request.Header.Set(originHeader, "https://example.com")
+	request.Header.Set(requestMethodHeader, "GET")
+
+	this.handler.ServeHTTP(this.response, request)
+
+	this.So(this.response.Code, should.Equal, http.StatusNoContent)
+	this.So(this.response.Header().Get(allowOriginHeader), should.Equal, "*")
+	this.So(this.response.Header().Get(allowHeaderName), should.BeBlank)
+}
+
+func (this *MethodsFixture) TestAllowedMethods_NormalizedWithoutDuplicates() {
+	this.So(allowedMethods([]string{"post", "GET", "head", "Options", "POST"}), should.Resemble,
+		[]string{"POST", "GET", "HEAD", "OPTIONS"})
+	this.So(allowedMethods(nil), should.Resemble, []string{"OPTIONS"})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type MethodRestrictedModel struct{ Value string }
+
+func (this *MethodRestrictedModel) Methods() []string { return []string{"get", "PATCH"} }
+func (this *MethodRestrictedModel) Bind(request *http.Request) error {
+	if request.Method == "DELETE" || request.Method == "OPTIONS" {
+		panic("should not bind")
+	}
+	this.Value = request.URL.Query().Get("value")
+	return nil
+}
@@ -12,7 +12,7 @@ type (
 	niladicAction func() Renderer
 )
 
-func NewFromFactory(inputModelFactory createModel, controllerAction interface{}) http.Handler {
+func NewFromFactory(inputModelFactory createModel, controllerAction interface{}, options ...Option) http.Handler {
 	expectedModelType := identifyInputModelArgumentType(controllerAction)
 	if expectedModelType == nil {
 		panic("Controller action must accept an input model.")
@@ -27,21 +27,22 @@ func NewFromFactory(inputModelFactory createModel, controllerAction interface{})
 		))
 	}
 
-	return withFactory(controllerAction, inputModelFactory)
+	return configure(withFactory(controllerAction, actualModelType, inputModelFactory), options)
 }
 
-func New(controllerAction interface{}) http.Handler {
+func New(controllerAction interface{}, options ...Option) http.Handler {
 	modelType := identifyInputModelArgumentType(controllerAction)
 	if modelType == nil {
-		return simple(controllerAction.(func() Renderer))
+		return configure(simple(controllerAction.(func() Renderer)), options)
 	}
 
-	return withFactory(controllerAction, func() interface{} {
+	return configure(withFactory(controllerAction, modelType, func() interface{} {
 		return reflect.New(modelType.Elem()).Interface()
-	})
+	}), options)
 }
 
-func withFactory(controllerAction interface{}, input createModel) http.Handler {
+func withFactory(controllerAction interface{}, modelType reflect.Type, input createModel) *actionHandler {
+	verifyCookieFields(modelType)
 	callbackType := reflect.ValueOf(controllerAction)
 	var callback monadicAction = func(m interface{}) Renderer {
 		results := callbackType.Call([]reflect.Value{reflect.ValueOf(m)})
@@ -51,10 +52,10 @@ func withFactory(controllerAction interface{}, input createModel) http.Handler {
 		}
 		return result.Elem().Interface().(Renderer)
 	}
-	return &actionHandler{controller: callback, generateNewInputModel: input}
+	return &actionHandler{controller: callback, generateNewInputModel: input, modelType: modelType}
 }
 
-func simple(controllerAction niladicAction) http.Handler {
+func simple(controllerAction niladicAction) *actionHandler {
 	return &actionHandler{
 		controller:            func(interface{}) Renderer { return controllerAction() },
 		generateNewInputModel: func() interface{} { return nil },
@@ -0,0 +1,48 @@
+package detour
+
+import (
+	"net/http"
+	"time"
+)
+
+// StatusClientClosedRequest is the (non-standard) status code reported for
+// requests abandoned by the client, following the convention of nginx.
+const StatusClientClosedRequest = 499
+
+// Outcome describes how a handler concluded a single request (see Observe).
+type Outcome struct {
+	Request    *http.Request
+	StatusCode int // StatusClientClosedRequest when abandoned
+	Duration   time.Duration
+
+	// Abandoned indicates that the request context was done (ie. the client
+	// disconnected) before the response was written, and so nothing was written.
+	Abandoned bool
+	Err       error // the error of the request context, when abandoned
+}
+
+// Observe registers a function (ie. for logging or metrics) to be called with
+// the outcome of each request served by a single handler, on the goroutine
+// that served the request.
+func Observe(observer func(Outcome)) Option {
+	return func(this *actionHandler) { this.observers = append(this.observers, observer) }
+}
+
+func (this *actionHandler) observe(outcome Outcome, request *http.Request, started time.Time) {
+	if len(this.observers) == 0 {
+		return
+	}
+	outcome.Request = request
+	outcome.Duration = time.Since(started)
+	for _, observer := range this.observers {
+		observer(outcome)
+	}
+}
+
+func abandoned(request *http.Request) bool {
+	return request.Context().Err() != nil
+}
+
+func abandon(request *http.Request) Outcome {
+	return Outcome{StatusCode: StatusClientClosedRequest, Abandoned: true, Err: request.Context().Err()}
+}
@@ -0,0 +1,153 @@
+package detour
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestObserveFixture(t *testing.T) {
+	gunit.Run(new(ObserveFixture), t)
+}
+
+type ObserveFixture struct {
+	*gunit.Fixture
+
+	outcomes []Outcome
+	cancel   context.CancelFunc
+	request  *http.Request
+	response *httptest.ResponseRecorder
+	rendered bool
+}
+
+func (this *ObserveFixture) Setup() {
+	ctx, cancel := context.WithCancel(context.Background())
+	this.cancel = cancel
+	this.request = httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	this.response = httptest.NewRecorder()
+}
+func (this *ObserveFixture) Teardown() {
+	this.cancel()
+}
+
+func (this *ObserveFixture) observe(outcome Outcome) {
+	this.outcomes = append(this.outcomes, outcome)
+}
+func (this *ObserveFixture) serve(action interface{}, options ...Option) {
+	handler := New(action, append(options, Observe(this.observe))...)
+	handler.ServeHTTP(this.response, this.request)
+}
+func (this *ObserveFixture) serveCancellingModel(options ...Option) {
+	handler := NewFromFactory(func() interface{} { return &CancellingModel{cancel: this.cancel} },
+		func(*CancellingModel) Renderer { panic("should not be called") },
+		append(options, Observe(this.observe))...)
+	handler.ServeHTTP(this.response, this.request)
+}
+func (this *ObserveFixture) assertAbandoned() {
+	this.So(this.outcomes, should.HaveLength, 1)
+	this.So(this.outcomes[0].Abandoned, should.BeTrue)
+	this.So(this.outcomes[0].StatusCode, should.Equal, StatusClientClosedRequest)
+	this.So(this.outcomes[0].Err, should.Equal, context.Canceled)
+	this.So(this.response.Header(), should.BeEmpty)
+	this.So(this.response.Body.Len(), should.Equal, 0)
+}
+
+func (this *ObserveFixture) Render(response http.ResponseWriter, _ *http.Request) {
+	this.rendered = true
+	response.WriteHeader(http.StatusCreated)
+	_, _ = response.Write([]byte("rendered"))
+}
+
+func (this *ObserveFixture) TestCompletedRequest_Observed() {
+	this.serve(func() Renderer { return this }, Observe(this.observe))
+
+	this.So(this.response.Code, should.Equal, http.StatusCreated)
+	this.So(this.outcomes, should.HaveLength, 2)
+	for _, outcome := range this.outcomes {
+		this.So(outcome.Request.URL.Path, should.Equal, "/")
+		this.So(outcome.StatusCode, should.Equal, http.StatusCreated)
+		this.So(outcome.Abandoned, should.BeFalse)
+		this.So(outcome.Err, should.BeNil)
+		this.So(outcome.Duration, should.BeGreaterThan, 0)
+	}
+}
+
+func (this *ObserveFixture) TestCancelledBeforeServing_ControllerNotCalled() {
+	this.cancel()
+
+	this.serve(func(*TimeoutModel) Renderer { panic("should not be called") })
+
+	this.assertAbandoned()
+}
+
+func (this *ObserveFixture) TestCancelledDuringBinding_ControllerNotCalled() {
+	this.serveCancellingModel()
+	this.assertAbandoned()
+}
+
+func (this *ObserveFixture) TestCancelledDuringBinding_WithTimeout_ControllerNotCalled() {
+	this.serveCancellingModel(Timeout(time.Minute, nil))
+	this.assertAbandoned()
+}
+
+func (this *ObserveFixture) TestCancelledDuringController_RenderSkipped() {
+	this.serve(func() Renderer {
+		this.cancel()
+		return this
+	})
+
+	this.So(this.rendered, should.BeFalse)
+	this.assertAbandoned()
+}
+
+func (this *ObserveFixture) TestCancelledDuringRender_FlushSkipped() {
+	this.serve(func() Renderer {
+		return CompoundRenderer{this, RendererFunc(func() { this.cancel() })}
+	})
+
+	this.So(this.rendered, should.BeTrue)
+	this.assertAbandoned()
+}
+
+func (this *ObserveFixture) TestCancelledDuringTimeout_AbandonedRatherThanTimedOut() {
+	release := make(chan struct{})
+	defer close(release)
+
+	this.serve(func(model *TimeoutModel) Renderer {
+		this.cancel()
+		<-release
+		return this
+	}, Timeout(time.Minute, nil))
+
+	this.assertAbandoned()
+}
+
+func (this *ObserveFixture) TestCORSPreflight_Observed() {
+	this.request = httptest.NewRequest("OPTIONS", "/", nil)
+	this.request.Header.Set(originHeader, "https://example.com")
+	this.request.Header.Set(requestMethodHeader, "GET")
+
+	this.serve(func() Renderer { return this }, CORS(CORSPolicy{AllowedOrigins: []string{"*"}}))
+
+	this.So(this.outcomes, should.HaveLength, 1)
+	this.So(this.outcomes[0].StatusCode, should.Equal, http.StatusNoContent)
+	this.So(this.rendered, should.BeFalse)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type CancellingModel struct{ cancel context.CancelFunc }
+
+func (this *CancellingModel) Bind(*http.Request) error {
+	this.cancel()
+	return nil
+}
+
+type RendererFunc func()
+
+func (this RendererFunc) Render(http.ResponseWriter, *http.Request) { this() }
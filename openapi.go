@@ -0,0 +1,473 @@
+package detour
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPIInfo populates the required "info" object of a generated OpenAPI document.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPI generates an OpenAPI 3.1 (JSON) document describing each recorded route.
+//
+// Because Bind() methods are opaque, the generator relies on struct tags on the
+// input model to describe where values come from and which rules apply to them:
+//
+//	type CreateUserInputModel struct {
+//	    Account string `bind:"path:account"`
+//	    Trace   string `bind:"header:X-Trace-Id"`
+//	    Verbose bool   `bind:"query:verbose"`
+//	    Name    string `json:"name" validate:"required,max=64" doc:"The display name."`
+//	}
+//
+// The bind tag takes the form "source:name" where source is one of query, form,
+// header, path, or cookie (the name defaults to the field name). Fields without
+// a bind tag describe the JSON request body of models which implement BindJSON.
+// The validate tag is a comma-separated list of: required, min=N, max=N, and
+// enum=a|b|c. The doc tag becomes the description of a parameter or property.
+// Other than cookies (see BindCookies), detour does not bind values from these tags.
+func (this *Registry) OpenAPI(info OpenAPIInfo) ([]byte, error) {
+	generator := newOpenAPIGenerator()
+	for _, route := range this.Routes() {
+		generator.add(route)
+	}
+	return json.MarshalIndent(generator.document(info), "", "  ")
+}
+
+type jsonObject map[string]interface{}
+
+type openAPIGenerator struct {
+	paths   map[string]jsonObject
+	schemas jsonObject
+	names   map[reflect.Type]string
+}
+
+func newOpenAPIGenerator() *openAPIGenerator {
+	return &openAPIGenerator{
+		paths:   make(map[string]jsonObject),
+		schemas: make(jsonObject),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+func (this *openAPIGenerator) document(info OpenAPIInfo) jsonObject {
+	return jsonObject{
+		"openapi":    openAPIVersion,
+		"info":       info,
+		"paths":      this.paths,
+		"components": jsonObject{"schemas": this.schemas},
+	}
+}
+
+func (this *openAPIGenerator) add(route Route) {
+	operations, found := this.paths[route.Path]
+	if !found {
+		operations = make(jsonObject)
+		this.paths[route.Path] = operations
+	}
+	operations[strings.ToLower(route.Method)] = this.operation(route)
+}
+
+func (this *openAPIGenerator) operation(route Route) jsonObject {
+	operation := jsonObject{"responses": this.responses(route)}
+
+	fields := modelFields(route.ModelType)
+	if parameters := this.parameters(route, fields); len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	if body := this.requestBody(route, fields); body != nil {
+		operation["requestBody"] = body
+	}
+	return operation
+}
+
+func (this *openAPIGenerator) parameters(route Route, fields []modelField) (parameters []jsonObject) {
+	declared := make(map[string]bool)
+	for _, field := range fields {
+		location := field.source
+		if location == bindForm && !hasRequestBody(route) {
+			location = bindQuery
+		}
+		if location == "" || location == bindForm {
+			continue
+		}
+		if location == bindPath {
+			declared[field.name] = true
+		}
+		parameter := jsonObject{
+			"name":     field.name,
+			"in":       location,
+			"required": field.required || location == bindPath,
+			"schema":   this.fieldSchema(field),
+		}
+		if len(field.doc) > 0 {
+			parameter["description"] = field.doc
+		}
+		parameters = append(parameters, parameter)
+	}
+
+	for _, match := range pathTemplateParameter.FindAllStringSubmatch(route.Path, -1) {
+		if name := match[1]; !declared[name] {
+			parameters = append(parameters, jsonObject{
+				"name":     name,
+				"in":       bindPath,
+				"required": true,
+				"schema":   jsonObject{"type": "string"},
+			})
+		}
+	}
+	return parameters
+}
+
+func (this *openAPIGenerator) requestBody(route Route, fields []modelField) jsonObject {
+	if !hasRequestBody(route) {
+		return nil
+	}
+	if bindsJSON(route.ModelType) {
+		return jsonObject{
+			"required": true,
+			"content":  jsonObject{jsonMediaType: jsonObject{"schema": this.schema(route.ModelType)}},
+		}
+	}
+
+	properties := make(jsonObject)
+	var required []string
+	for _, field := range fields {
+		if field.source != bindForm {
+			continue
+		}
+		properties[field.name] = this.fieldSchema(field)
+		if field.required {
+			required = append(required, field.name)
+		}
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	schema := jsonObject{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return jsonObject{"content": jsonObject{formMediaType: jsonObject{"schema": schema}}}
+}
+
+func (this *openAPIGenerator) responses(route Route) jsonObject {
+	responses := this.declaredResponses(route.Responses)
+	if route.ModelType == nil {
+		return responses
+	}
+	if route.ModelType.Implements(binderType) || bindsJSON(route.ModelType) {
+		this.addErrorsResponse(responses, http.StatusBadRequest)
+	}
+	if route.ModelType.Implements(validatorType) {
+		this.addErrorsResponse(responses, http.StatusUnprocessableEntity)
+	}
+	return responses
+}
+
+func (this *openAPIGenerator) declaredResponses(declarations []ResponseDeclaration) jsonObject {
+	if len(declarations) == 0 {
+		return jsonObject{strconv.Itoa(http.StatusOK): jsonObject{"description": http.StatusText(http.StatusOK)}}
+	}
+
+	responses := make(jsonObject)
+	for _, declared := range declarations {
+		code := strconv.Itoa(declared.StatusCode)
+		response, found := responses[code].(jsonObject)
+		if !found {
+			response = jsonObject{"description": http.StatusText(declared.StatusCode)}
+			responses[code] = response
+		}
+		if declared.Type == nil {
+			continue
+		}
+		content, found := response["content"].(jsonObject)
+		if !found {
+			content = make(jsonObject)
+			response["content"] = content
+		}
+		media := mediaType(firstNonBlank(declared.ContentType, jsonMediaType))
+		content[media] = jsonObject{"schema": this.schema(declared.Type)}
+	}
+	return responses
+}
+
+func (this *openAPIGenerator) addErrorsResponse(responses jsonObject, statusCode int) {
+	code := strconv.Itoa(statusCode)
+	if _, declared := responses[code]; !declared {
+		responses[code] = this.errorsResponse(statusCode)
+	}
+}
+
+func (this *openAPIGenerator) errorsResponse(statusCode int) jsonObject {
+	if _, found := this.schemas[errorsSchemaName]; !found {
+		this.schemas[errorsSchemaName] = jsonObject{
+			"type":  "array",
+			"items": this.schema(reflect.TypeOf(InputError{})),
+		}
+	}
+	return jsonObject{
+		"description": http.StatusText(statusCode),
+		"content": jsonObject{
+			jsonMediaType: jsonObject{"schema": schemaReference(errorsSchemaName)},
+		},
+	}
+}
+
+func (this *openAPIGenerator) fieldSchema(field modelField) jsonObject {
+	schema := this.schema(field.field.Type)
+	if _, isReference := schema["$ref"]; isReference && !field.hasRules() {
+		return schema
+	}
+	annotated := make(jsonObject, len(schema))
+	for key, value := range schema {
+		annotated[key] = value
+	}
+	field.annotate(annotated)
+	return annotated
+}
+
+func (this *openAPIGenerator) schema(target reflect.Type) jsonObject {
+	for target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+
+	switch target {
+	case timeType:
+		return jsonObject{"type": "string", "format": "date-time"}
+	case bytesType:
+		return jsonObject{"type": "string", "format": "byte"}
+	}
+
+	switch target.Kind() {
+	case reflect.Bool:
+		return jsonObject{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16:
+		return jsonObject{"type": "integer"}
+	case reflect.Int32:
+		return jsonObject{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return jsonObject{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return jsonObject{"type": "integer", "minimum": 0}
+	case reflect.Float32:
+		return jsonObject{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return jsonObject{"type": "number", "format": "double"}
+	case reflect.String:
+		return jsonObject{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return jsonObject{"type": "array", "items": this.schema(target.Elem())}
+	case reflect.Map:
+		return jsonObject{"type": "object", "additionalProperties": this.schema(target.Elem())}
+	case reflect.Struct:
+		return this.structSchema(target)
+	default:
+		return jsonObject{}
+	}
+}
+
+func (this *openAPIGenerator) structSchema(target reflect.Type) jsonObject {
+	if target.Name() == "" {
+		return this.objectSchema(target)
+	}
+	if name, found := this.names[target]; found {
+		return schemaReference(name)
+	}
+
+	name := this.schemaName(target)
+	this.names[target] = name
+	this.schemas[name] = jsonObject{} // placeholder (allows recursive types)
+	this.schemas[name] = this.objectSchema(target)
+	return schemaReference(name)
+}
+
+func (this *openAPIGenerator) schemaName(target reflect.Type) string {
+	name := target.Name()
+	if _, taken := this.schemas[name]; taken {
+		name = path.Base(target.PkgPath()) + "." + name
+	}
+	return name
+}
+
+func (this *openAPIGenerator) objectSchema(target reflect.Type) jsonObject {
+	properties := make(jsonObject)
+	var required []string
+	for _, field := range modelFields(target) {
+		if field.source != "" || field.jsonName == "" {
+			continue
+		}
+		properties[field.jsonName] = this.fieldSchema(field)
+		if field.required {
+			required = append(required, field.jsonName)
+		}
+	}
+
+	schema := jsonObject{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func schemaReference(name string) jsonObject {
+	return jsonObject{"$ref": "#/components/schemas/" + name}
+}
+
+func hasRequestBody(route Route) bool {
+	return route.Method == http.MethodPost || route.Method == http.MethodPut || route.Method == http.MethodPatch
+}
+
+func bindsJSON(modelType reflect.Type) bool {
+	if modelType == nil || !modelType.Implements(bindJSONType) {
+		return false
+	}
+	return newModel(modelType).(BindJSON).BindJSON()
+}
+
+func newModel(modelType reflect.Type) interface{} {
+	if modelType.Kind() == reflect.Ptr {
+		return reflect.New(modelType.Elem()).Interface()
+	}
+	return reflect.Zero(modelType).Interface()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type modelField struct {
+	field    reflect.StructField
+	source   string
+	name     string
+	jsonName string
+	doc      string
+
+	required bool
+	minimum  string
+	maximum  string
+	enum     []string
+}
+
+func modelFields(target reflect.Type) (fields []modelField) {
+	if target == nil {
+		return nil
+	}
+	for target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+	if target.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for x := 0; x < target.NumField(); x++ {
+		field := target.Field(x)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			fields = append(fields, modelFields(field.Type)...)
+			continue
+		}
+		if field.PkgPath != "" || field.Type == contextType {
+			continue // unexported
+		}
+		fields = append(fields, parseModelField(field))
+	}
+	return fields
+}
+
+func parseModelField(field reflect.StructField) modelField {
+	parsed := modelField{field: field, doc: field.Tag.Get("doc")}
+
+	if bind := field.Tag.Get("bind"); len(bind) > 0 {
+		parsed.source, parsed.name = splitPair(bind, ":")
+		if parsed.name == "" {
+			parsed.name = field.Name
+		}
+	}
+
+	parsed.jsonName = field.Name
+	if name, _ := splitPair(field.Tag.Get("json"), ","); name == "-" {
+		parsed.jsonName = ""
+	} else if len(name) > 0 {
+		parsed.jsonName = name
+	}
+
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		switch key, value := splitPair(strings.TrimSpace(rule), "="); key {
+		case "required":
+			parsed.required = true
+		case "min":
+			parsed.minimum = value
+		case "max":
+			parsed.maximum = value
+		case "enum":
+			parsed.enum = strings.Split(value, "|")
+		}
+	}
+	return parsed
+}
+
+func (this modelField) hasRules() bool {
+	return len(this.doc) > 0 || len(this.minimum) > 0 || len(this.maximum) > 0 || len(this.enum) > 0
+}
+
+func (this modelField) annotate(schema jsonObject) {
+	if len(this.doc) > 0 {
+		schema["description"] = this.doc
+	}
+	if len(this.enum) > 0 {
+		schema["enum"] = this.enum
+	}
+
+	minimumKey, maximumKey := "minimum", "maximum"
+	switch schema["type"] {
+	case "string":
+		minimumKey, maximumKey = "minLength", "maxLength"
+	case "array":
+		minimumKey, maximumKey = "minItems", "maxItems"
+	}
+	if value, err := strconv.ParseFloat(this.minimum, 64); err == nil {
+		schema[minimumKey] = value
+	}
+	if value, err := strconv.ParseFloat(this.maximum, 64); err == nil {
+		schema[maximumKey] = value
+	}
+}
+
+func splitPair(value, separator string) (string, string) {
+	if index := strings.Index(value, separator); index >= 0 {
+		return value[:index], value[index+len(separator):]
+	}
+	return value, ""
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var (
+	binderType    = reflect.TypeOf((*Binder)(nil)).Elem()
+	bindJSONType  = reflect.TypeOf((*BindJSON)(nil)).Elem()
+	validatorType = reflect.TypeOf((*Validator)(nil)).Elem()
+	contextType   = reflect.TypeOf((*context.Context)(nil)).Elem()
+	timeType      = reflect.TypeOf(time.Time{})
+	bytesType     = reflect.TypeOf([]byte(nil))
+
+	pathTemplateParameter = regexp.MustCompile(`{([^{}]+)}`)
+)
+
+const (
+	openAPIVersion   = "3.1.0"
+	errorsSchemaName = "Errors"
+	jsonMediaType    = "application/json"
+	formMediaType    = "application/x-www-form-urlencoded"
+	bindQuery        = "query"
+	bindForm         = "form"
+	bindPath         = "path"
+)
@@ -0,0 +1,279 @@
+package detour
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestOpenAPIFixture(t *testing.T) {
+	gunit.Run(new(OpenAPIFixture), t)
+}
+
+type OpenAPIFixture struct {
+	*gunit.Fixture
+
+	registry *Registry
+	document map[string]interface{}
+}
+
+func (this *OpenAPIFixture) Setup() {
+	this.registry = NewRegistry()
+}
+
+func (this *OpenAPIFixture) generate() {
+	raw, err := this.registry.OpenAPI(OpenAPIInfo{Title: "Users", Version: "1.0"})
+	this.So(err, should.BeNil)
+	this.document = nil
+	this.So(json.Unmarshal(raw, &this.document), should.BeNil)
+}
+func (this *OpenAPIFixture) lookup(keys ...string) interface{} {
+	var value interface{} = this.document
+	for _, key := range keys {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+func (this *OpenAPIFixture) operation(path, method string) map[string]interface{} {
+	operation, _ := this.lookup("paths", path, method).(map[string]interface{})
+	return operation
+}
+func (this *OpenAPIFixture) parameter(path, method, name string) map[string]interface{} {
+	parameters, _ := this.operation(path, method)["parameters"].([]interface{})
+	for _, parameter := range parameters {
+		if object := parameter.(map[string]interface{}); object["name"] == name {
+			return object
+		}
+	}
+	return nil
+}
+
+func (this *OpenAPIFixture) TestDocumentHeader() {
+	this.generate()
+
+	this.So(this.document["openapi"], should.Equal, "3.1.0")
+	this.So(this.lookup("info", "title"), should.Equal, "Users")
+	this.So(this.lookup("info", "version"), should.Equal, "1.0")
+}
+
+func (this *OpenAPIFixture) TestNiladicAction_OnlyOKResponse() {
+	this.registry.Handle("GET", "/ping", New(func() Renderer { return nil }))
+
+	this.generate()
+
+	this.So(this.lookup("paths", "/ping", "get", "responses"), should.Resemble, map[string]interface{}{
+		"200": map[string]interface{}{"description": "OK"},
+	})
+}
+
+func (this *OpenAPIFixture) TestParametersFromBindTags() {
+	this.registry.Handle("GET", "/accounts/{account}/users", New(func(*DocumentedQueryModel) Renderer { return nil }))
+
+	this.generate()
+
+	this.So(this.parameter("/accounts/{account}/users", "get", "account"), should.Resemble, map[string]interface{}{
+		"name": "account", "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"},
+	})
+	this.So(this.parameter("/accounts/{account}/users", "get", "X-Trace-Id"), should.Resemble, map[string]interface{}{
+		"name": "X-Trace-Id", "in": "header", "required": false, "schema": map[string]interface{}{"type": "string"},
+	})
+	this.So(this.parameter("/accounts/{account}/users", "get", "limit"), should.Resemble, map[string]interface{}{
+		"name": "limit", "in": "query", "required": true, "description": "Page size.",
+		"schema": map[string]interface{}{"type": "integer", "minimum": 1.0, "maximum": 100.0, "description": "Page size."},
+	})
+	this.So(this.parameter("/accounts/{account}/users", "get", "sort"), should.Resemble, map[string]interface{}{
+		"name": "sort", "in": "query", "required": false,
+		"schema": map[string]interface{}{"type": "string", "enum": []interface{}{"name", "created"}},
+	})
+	this.So(this.parameter("/accounts/{account}/users", "get", "Context"), should.BeNil)
+}
+
+func (this *OpenAPIFixture) TestUndeclaredPathTemplateParameter_AddedAsRequiredString() {
+	this.registry.Handle("GET", "/users/{id}", New(func() Renderer { return nil }))
+
+	this.generate()
+
+	this.So(this.parameter("/users/{id}", "get", "id"), should.Resemble, map[string]interface{}{
+		"name": "id", "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"},
+	})
+}
+
+func (this *OpenAPIFixture) TestFormFields_RequestBodyForPOST() {
+	this.registry.Handle("POST", "/login", New(func(*DocumentedFormModel) Renderer { return nil }))
+
+	this.generate()
+
+	this.So(this.lookup("paths", "/login", "post", "requestBody"), should.Resemble, map[string]interface{}{
+		"content": map[string]interface{}{
+			"application/x-www-form-urlencoded": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type":     "object",
+					"required": []interface{}{"username"},
+					"properties": map[string]interface{}{
+						"username": map[string]interface{}{"type": "string"},
+						"password": map[string]interface{}{"type": "string", "minLength": 8.0},
+					},
+				},
+			},
+		},
+	})
+	this.So(this.operation("/login", "post")["parameters"], should.BeNil)
+}
+
+func (this *OpenAPIFixture) TestFormFields_QueryParametersForGET() {
+	this.registry.Handle("GET", "/login", New(func(*DocumentedFormModel) Renderer { return nil }))
+
+	this.generate()
+
+	this.So(this.parameter("/login", "get", "username"), should.NotBeNil)
+	this.So(this.parameter("/login", "get", "username")["in"], should.Equal, "query")
+	this.So(this.operation("/login", "get")["requestBody"], should.BeNil)
+}
+
+func (this *OpenAPIFixture) TestJSONBody_ReferencesComponentSchema() {
+	this.registry.Handle("PUT", "/users/{id}", New(func(*DocumentedJSONModel) Renderer { return nil }))
+
+	this.generate()
+
+	this.So(this.lookup("paths", "/users/{id}", "put", "requestBody"), should.Resemble, map[string]interface{}{
+		"required": true,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/DocumentedJSONModel"},
+			},
+		},
+	})
+	this.So(this.lookup("components", "schemas", "DocumentedJSONModel"), should.Resemble, map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"name"},
+		"properties": map[string]interface{}{
+			"name":    map[string]interface{}{"type": "string", "maxLength": 64.0},
+			"tags":    map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"born":    map[string]interface{}{"type": "string", "format": "date-time"},
+			"Address": map[string]interface{}{"$ref": "#/components/schemas/DocumentedAddress"},
+		},
+	})
+	this.So(this.lookup("components", "schemas", "DocumentedAddress"), should.Resemble, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"street": map[string]interface{}{"type": "string"},
+			"next":   map[string]interface{}{"$ref": "#/components/schemas/DocumentedAddress"},
+		},
+	})
+}
+
+func (this *OpenAPIFixture) TestJSONBindingDisabled_NoRequestBody() {
+	this.registry.Handle("POST", "/", New(func(*BindingFromJSONDisabled) Renderer { return nil }))
+
+	this.generate()
+
+	this.So(this.operation("/", "post")["requestBody"], should.BeNil)
+}
+
+func (this *OpenAPIFixture) TestBindingAndValidatingModel_ErrorsResponses() {
+	this.registry.Handle("PUT", "/users/{id}", New(func(*DocumentedJSONModel) Renderer { return nil }))
+
+	this.generate()
+
+	responses := this.lookup("paths", "/users/{id}", "put", "responses").(map[string]interface{})
+	this.So(responses, should.ContainKey, "200")
+	for _, code := range []string{"400", "422"} {
+		this.So(responses[code], should.Resemble, map[string]interface{}{
+			"description": http.StatusText(map[string]int{"400": 400, "422": 422}[code]),
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{"$ref": "#/components/schemas/Errors"},
+				},
+			},
+		})
+	}
+	this.So(this.lookup("components", "schemas", "Errors"), should.Resemble, map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"$ref": "#/components/schemas/InputError"},
+	})
+	this.So(this.lookup("components", "schemas", "InputError", "properties"), should.Resemble, map[string]interface{}{
+		"fields":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		"message": map[string]interface{}{"type": "string"},
+	})
+}
+
+func (this *OpenAPIFixture) TestModelWithoutBindingOrValidation_NoErrorsResponses() {
+	this.registry.Handle("GET", "/", NewFromFactory(NewBlankBasicInputModel, (&Controller{}).HandleBasicInputModel))
+
+	this.generate()
+
+	responses := this.lookup("paths", "/", "get", "responses").(map[string]interface{})
+	this.So(responses, should.HaveLength, 1)
+}
+
+func (this *OpenAPIFixture) TestDeclaredResponses_ReplaceDefaultOKResponse() {
+	this.registry.Handle("GET", "/users/{id}", New(func(*DocumentedQueryModel) Renderer { return nil },
+		DeclareResponse(http.StatusOK, jsonContentType, (*DocumentedAddress)(nil)),
+		DeclareResponse(http.StatusOK, "application/xml", DocumentedAddress{}),
+		DeclareResponse(http.StatusBadRequest, "text/plain", ""),
+		DeclareResponse(http.StatusNotFound, "", nil),
+	))
+
+	this.generate()
+
+	this.So(this.lookup("paths", "/users/{id}", "get", "responses"), should.Resemble, map[string]interface{}{
+		"200": map[string]interface{}{
+			"description": "OK",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/DocumentedAddress"}},
+				"application/xml":  map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/DocumentedAddress"}},
+			},
+		},
+		"400": map[string]interface{}{
+			"description": "Bad Request",
+			"content": map[string]interface{}{
+				"text/plain": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+			},
+		},
+		"404": map[string]interface{}{"description": "Not Found"},
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type DocumentedQueryModel struct {
+	ContextBinder
+
+	Account string `bind:"path:account"`
+	Trace   string `bind:"header:X-Trace-Id"`
+	Limit   int    `bind:"query:limit" validate:"required,min=1,max=100" doc:"Page size."`
+	Sort    string `bind:"query:sort" validate:"enum=name|created"`
+}
+
+func (this *DocumentedQueryModel) Bind(*http.Request) error { return nil }
+
+type DocumentedFormModel struct {
+	Username string `bind:"form:username" validate:"required"`
+	Password string `bind:"form:password" validate:"min=8"`
+}
+
+type DocumentedJSONModel struct {
+	ID      string    `bind:"path:id"`
+	Name    string    `json:"name" validate:"required,max=64"`
+	Tags    []string  `json:"tags,omitempty"`
+	Born    time.Time `json:"born"`
+	Address *DocumentedAddress
+	secret  string
+	Ignored string `json:"-"`
+}
+
+func (this *DocumentedJSONModel) BindJSON() bool  { return true }
+func (this *DocumentedJSONModel) Validate() error { return nil }
+
+type DocumentedAddress struct {
+	Street string             `json:"street"`
+	Next   *DocumentedAddress `json:"next"`
+}
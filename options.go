@@ -0,0 +1,13 @@
+package detour
+
+import "net/http"
+
+// Option configures a single handler as it is created by New or NewFromFactory.
+type Option func(*actionHandler)
+
+func configure(handler *actionHandler, options []Option) http.Handler {
+	for _, option := range options {
+		option(handler)
+	}
+	return handler
+}
@@ -0,0 +1,50 @@
+package detour
+
+// Stage identifies a step of the input model pipeline (see Prepare).
+type Stage int
+
+const (
+	StageNone Stage = iota
+	StageBind
+	StageValidate
+	StageServerError
+)
+
+func (this Stage) String() string {
+	switch this {
+	case StageBind:
+		return "Bind"
+	case StageValidate:
+		return "Validate"
+	case StageServerError:
+		return "ServerError"
+	default:
+		return "None"
+	}
+}
+
+// Preparation is the outcome of Prepare. When all stages succeed the
+// Stage is StageNone, the StatusCode is 0, and the Error is nil.
+type Preparation struct {
+	Stage      Stage // the stage that failed
+	StatusCode int   // the status code of the error response
+	Error      error
+}
+
+func newPreparation(stage Stage, err error, defaultStatusCode int) Preparation {
+	statusCode, err := statusCodeFromErrorOrDefault(err, defaultStatusCode)
+	return Preparation{Stage: stage, StatusCode: statusCode, Error: err}
+}
+
+func (this Preparation) Succeeded() bool {
+	return this.Error == nil
+}
+
+// Renderer returns the error response that would be written in place of calling
+// the controller action, or nil if the preparation succeeded.
+func (this Preparation) Renderer() Renderer {
+	if this.Succeeded() {
+		return nil
+	}
+	return inputModelErrorResult(this.StatusCode, this.Error)
+}
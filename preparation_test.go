@@ -0,0 +1,83 @@
+package detour
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestPreparationFixture(t *testing.T) {
+	gunit.Run(new(PreparationFixture), t)
+}
+
+type PreparationFixture struct {
+	*gunit.Fixture
+
+	request *http.Request
+}
+
+func (this *PreparationFixture) Setup() {
+	this.request = httptest.NewRequest("GET", "/?binding=BindingInputModel", nil)
+}
+
+func (this *PreparationFixture) TestAllStagesSucceed() {
+	model := &SanitizingInputModel{}
+
+	preparation := Prepare(this.request, model)
+
+	this.So(preparation, should.Resemble, Preparation{})
+	this.So(preparation.Succeeded(), should.BeTrue)
+	this.So(preparation.Renderer(), should.BeNil)
+	this.So(model.Content, should.Equal, "SANITIZINGINPUTMODEL")
+}
+
+func (this *PreparationFixture) TestEmptyErrorsCountAsSuccess() {
+	this.So(Prepare(this.request, &BindingEmptyErrorsInputModel{}).Succeeded(), should.BeTrue)
+	this.So(Prepare(this.request, &BindingEmptyDiagnosticErrorsInputModel{}).Succeeded(), should.BeTrue)
+	this.So(Prepare(this.request, &ValidatingEmptyErrorsInputModel{}).Succeeded(), should.BeTrue)
+	this.So(Prepare(this.request, &ValidatingEmptyDiagnosticErrorsInputModel{}).Succeeded(), should.BeTrue)
+}
+
+func (this *PreparationFixture) TestBindFailure() {
+	preparation := Prepare(this.request, &BindingFailsInputModel{})
+
+	this.So(preparation.Stage, should.Equal, StageBind)
+	this.So(preparation.StatusCode, should.Equal, http.StatusBadRequest)
+	this.So(preparation.Error.Error(), should.Equal, `[{"Problem":"BindingFailsInputModel"}]`)
+	this.So(preparation.Renderer(), should.Resemble, &JSONResult{StatusCode: http.StatusBadRequest, Content: preparation.Error})
+}
+
+func (this *PreparationFixture) TestBindFailureWithCustomStatusCode() {
+	preparation := Prepare(this.request, &BindingFailsWithCustomStatusCodeInputModel{})
+
+	this.So(preparation.Stage, should.Equal, StageBind)
+	this.So(preparation.StatusCode, should.Equal, http.StatusTeapot)
+}
+
+func (this *PreparationFixture) TestValidateFailure() {
+	preparation := Prepare(this.request, &ValidatingFailsInputModel{})
+
+	this.So(preparation.Stage, should.Equal, StageValidate)
+	this.So(preparation.Stage.String(), should.Equal, "Validate")
+	this.So(preparation.StatusCode, should.Equal, http.StatusUnprocessableEntity)
+}
+
+func (this *PreparationFixture) TestServerError() {
+	preparation := Prepare(this.request, &FinalErrorInputModel{})
+
+	this.So(preparation.Stage, should.Equal, StageServerError)
+	this.So(preparation.StatusCode, should.Equal, http.StatusInternalServerError)
+	this.So(preparation.Renderer(), should.Resemble, &StatusCodeResult{
+		StatusCode: http.StatusInternalServerError,
+		Message:    "Internal Server Error",
+	})
+}
+
+func (this *PreparationFixture) TestStageNames() {
+	this.So(StageNone.String(), should.Equal, "None")
+	this.So(StageBind.String(), should.Equal, "Bind")
+	this.So(StageServerError.String(), should.Equal, "ServerError")
+}
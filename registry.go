@@ -0,0 +1,52 @@
+package detour
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Registry records the method, path, and input model type of each handler
+// passed to Handle so that documentation (see OpenAPI) can be generated from
+// the same handlers that serve requests.
+type Registry struct {
+	mutex  sync.Mutex
+	routes []Route
+}
+
+type Route struct {
+	Method    string
+	Path      string
+	ModelType reflect.Type // nil for niladic controller actions and non-detour handlers
+	Responses []ResponseDeclaration
+}
+
+func NewRegistry() *Registry {
+	return new(Registry)
+}
+
+// Handle records the route and returns the handler unchanged so that calls
+// may be nested in whatever routing mechanism is already in use:
+//
+//	mux.Handle("/users", registry.Handle("GET", "/users", detour.New(controller.ListUsers)))
+func (this *Registry) Handle(method, path string, handler http.Handler) http.Handler {
+	route := Route{Method: strings.ToUpper(method), Path: path}
+	if action, ok := handler.(*actionHandler); ok {
+		route.ModelType = action.modelType
+		route.Responses = action.responses
+	}
+
+	this.mutex.Lock()
+	this.routes = append(this.routes, route)
+	this.mutex.Unlock()
+
+	return handler
+}
+
+// Routes returns a copy of the routes recorded thus far, in registration order.
+func (this *Registry) Routes() []Route {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return append([]Route(nil), this.routes...)
+}
@@ -0,0 +1,45 @@
+package detour
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestRegistryFixture(t *testing.T) {
+	gunit.Run(new(RegistryFixture), t)
+}
+
+type RegistryFixture struct {
+	*gunit.Fixture
+
+	controller *Controller
+	registry   *Registry
+}
+
+func (this *RegistryFixture) Setup() {
+	this.controller = &Controller{}
+	this.registry = NewRegistry()
+}
+
+func (this *RegistryFixture) TestHandlerReturnedUnchanged() {
+	handler := New(this.controller.HandleBindingInputModel)
+	this.So(this.registry.Handle("GET", "/", handler), should.Equal, handler)
+}
+
+func (this *RegistryFixture) TestRoutesRecordedInOrderWithModelTypes() {
+	this.registry.Handle("get", "/binding", New(this.controller.HandleBindingInputModel))
+	this.registry.Handle("POST", "/factory", NewFromFactory(NewBlankBasicInputModel, this.controller.HandleBasicInputModel))
+	this.registry.Handle("GET", "/niladic", New(this.controller.HandleNoInputModel))
+	this.registry.Handle("GET", "/other", http.NotFoundHandler())
+
+	this.So(this.registry.Routes(), should.Resemble, []Route{
+		{Method: "GET", Path: "/binding", ModelType: reflect.TypeOf(&BindingInputModel{})},
+		{Method: "POST", Path: "/factory", ModelType: reflect.TypeOf(&BlankBasicInputModel{})},
+		{Method: "GET", Path: "/niladic"},
+		{Method: "GET", Path: "/other"},
+	})
+}
@@ -1,14 +1,10 @@
 package detour
 
-import (
-	"bytes"
-	"encoding/json"
-	"net/http"
-)
+import "net/http"
 
-func writeJSONResponse(response http.ResponseWriter, statusCode int, content interface{}, contentType, indent string) {
+func writeJSONResponse(response http.ResponseWriter, request *http.Request, statusCode int, content interface{}, contentType, indent string) {
 	writeContentType(response, contentType)
-	serialized, err := serializeJSON(content, indent)
+	serialized, err := currentSettings(request).encodeJSON(content, indent)
 	writeResponse(response, statusCode, serialized, err)
 }
 
@@ -24,14 +20,7 @@ func writeContentType(response http.ResponseWriter, contentType string) {
 }
 
 func serializeJSON(content interface{}, indent string) ([]byte, error) {
-	writer := new(bytes.Buffer)
-	encoder := json.NewEncoder(writer)
-	encoder.SetIndent("", indent)
-	if err := encoder.Encode(content); err != nil {
-		return nil, err
-	} else {
-		return writer.Bytes(), nil
-	}
+	return JSONEncoding{}.encode(defaultSettings.codec(), content, indent)
 }
 
 func writeResponse(response http.ResponseWriter, statusCode int, content []byte, previous error) {
@@ -72,8 +61,11 @@ func firstNonBlank(values ...string) string {
 }
 
 const (
-	contentTypeHeader      = "Content-Type"
-	jsonContentType        = "application/json; charset=utf-8"
-	octetStreamContentType = "application/octet-stream"
-	plaintextContentType   = "text/plain; charset=utf-8"
+	contentTypeHeader        = "Content-Type"
+	contentTypeOptionsHeader = "X-Content-Type-Options"
+	htmlContentType          = "text/html; charset=utf-8"
+	javascriptContentType    = "application/javascript; charset=utf-8"
+	jsonContentType          = "application/json; charset=utf-8"
+	octetStreamContentType   = "application/octet-stream"
+	plaintextContentType     = "text/plain; charset=utf-8"
 )
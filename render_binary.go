@@ -1,15 +1,33 @@
 package detour
 
-import "net/http"
+import (
+	"bytes"
+	"net/http"
+	"time"
+)
 
 type BinaryResult struct {
 	StatusCode  int
 	ContentType string
 	Content     []byte
+
+	ETag         string    // explicit entity tag, quoted if necessary
+	GenerateETag bool      // derive a strong ETag from the Content (ignored when ETag is set)
+	LastModified time.Time // omitted when zero
+
+	// AcceptRanges enables byte-range requests (see ReadSeekerResult) for 200 OK responses.
+	AcceptRanges bool
 }
 
-func (this BinaryResult) Render(response http.ResponseWriter, _ *http.Request) {
+func (this BinaryResult) Render(response http.ResponseWriter, request *http.Request) {
 	contentType := firstNonBlank(this.ContentType, octetStreamContentType)
-	writeContentTypeAndStatusCode(response, this.StatusCode, contentType)
-	response.Write(this.Content)
+	writeValidators(response, this.ETag, this.GenerateETag, this.Content, this.LastModified)
+
+	if this.AcceptRanges && orOK(this.StatusCode) == http.StatusOK {
+		writeContentType(response, contentType)
+		http.ServeContent(response, request, "", this.LastModified, bytes.NewReader(this.Content))
+	} else {
+		writeContentTypeAndStatusCode(response, this.StatusCode, contentType)
+		response.Write(this.Content)
+	}
 }
@@ -1,6 +1,10 @@
 package detour
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/smartystreets/assertions/should"
+)
 
 func (this *ResultFixture) TestBinaryResult() {
 	result := BinaryResult{
@@ -37,3 +41,45 @@ func (this *ResultFixture) TestBinaryResult_StatusCodeDefaultsTo200() {
 
 	this.assertStatusCode(http.StatusOK)
 }
+func (this *ResultFixture) TestBinaryResult_GeneratedETag() {
+	result := BinaryResult{
+		Content:      []byte("Hello, World!"),
+		GenerateETag: true,
+	}
+
+	this.render(result)
+
+	this.So(this.response.Header().Get(etagHeader), should.Equal, generateETag([]byte("Hello, World!")))
+}
+func (this *ResultFixture) TestBinaryResult_AcceptRanges() {
+	this.request.Header.Set("Range", "bytes=7-")
+	result := BinaryResult{
+		Content:      []byte("Hello, World!"),
+		AcceptRanges: true,
+	}
+
+	this.render(result)
+
+	this.assertStatusCode(http.StatusPartialContent)
+	this.assertContent("World!")
+	this.assertHasHeader("Accept-Ranges", "bytes")
+	this.assertHasHeader("Content-Range", "bytes 7-12/13")
+	this.assertHasHeader(contentTypeHeader, octetStreamContentType)
+}
+func (this *ResultFixture) TestBinaryResult_AcceptRanges_IfRangeMatchesETag() {
+	this.request.Header.Set("Range", "bytes=0-4")
+	this.request.Header.Set("If-Range", `"v1"`)
+
+	this.render(BinaryResult{Content: []byte("Hello, World!"), AcceptRanges: true, ETag: "v1"})
+
+	this.assertStatusCode(http.StatusPartialContent)
+	this.assertContent("Hello")
+}
+func (this *ResultFixture) TestBinaryResult_AcceptRanges_IgnoredForOtherStatusCodes() {
+	this.request.Header.Set("Range", "bytes=7-")
+
+	this.render(BinaryResult{StatusCode: http.StatusCreated, Content: []byte("Hello, World!"), AcceptRanges: true})
+
+	this.assertStatusCode(http.StatusCreated)
+	this.assertContent("Hello, World!")
+}
@@ -1,12 +1,19 @@
 package detour
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 type ContentResult struct {
 	StatusCode  int
 	ContentType string
 	Content     string
 	Headers     map[string]string // TODO: do we even need/use this?
+
+	ETag         string    // explicit entity tag, quoted if necessary
+	GenerateETag bool      // derive a strong ETag from the Content (ignored when ETag is set)
+	LastModified time.Time // omitted when zero
 }
 
 func (this ContentResult) Render(response http.ResponseWriter, _ *http.Request) {
@@ -19,6 +26,8 @@ func (this ContentResult) Render(response http.ResponseWriter, _ *http.Request)
 		}
 	}
 
+	content := []byte(this.Content)
+	writeValidators(response, this.ETag, this.GenerateETag, content, this.LastModified)
 	writeContentTypeAndStatusCode(response, this.StatusCode, contentType)
-	response.Write([]byte(this.Content))
+	response.Write(content)
 }
@@ -2,6 +2,7 @@ package detour
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/smartystreets/assertions/should"
 )
@@ -62,3 +63,17 @@ func (this *ResultFixture) TestContentResult_StatusCodeDefaultsTo200() {
 
 	this.assertStatusCode(http.StatusOK)
 }
+func (this *ResultFixture) TestContentResult_ExplicitValidators() {
+	modified := time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("MST", -7*60*60))
+	result := ContentResult{
+		Content:      "Hello, World!",
+		ETag:         "v1",
+		GenerateETag: true,
+		LastModified: modified,
+	}
+
+	this.render(result)
+
+	this.So(this.response.Header().Get(etagHeader), should.Equal, `"v1"`)
+	this.assertHasHeader(lastModifiedHeader, "Thu, 02 Jan 2020 10:04:05 GMT")
+}
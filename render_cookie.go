@@ -7,6 +7,7 @@ type CookieResult struct {
 	Cookie2 *http.Cookie
 	Cookie3 *http.Cookie
 	Cookie4 *http.Cookie
+	Cookies []*http.Cookie // set after Cookie1..Cookie4
 }
 
 func (this CookieResult) Render(response http.ResponseWriter, _ *http.Request) {
@@ -15,4 +16,9 @@ func (this CookieResult) Render(response http.ResponseWriter, _ *http.Request) {
 			http.SetCookie(response, cookie)
 		}
 	}
+	for _, cookie := range this.Cookies {
+		if cookie != nil {
+			http.SetCookie(response, cookie)
+		}
+	}
 }
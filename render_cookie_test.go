@@ -20,3 +20,19 @@ func (this *ResultFixture) TestCookieResult() {
 	this.So(this.response.Header()["Set-Cookie"], should.Resemble, []string{"a=1", "b=2", "d=4"})
 	this.assertContent("")
 }
+func (this *ResultFixture) TestCookieResult_Cookies() {
+	result := CookieResult{
+		Cookie1: &http.Cookie{Name: "a", Value: "1"},
+		Cookies: []*http.Cookie{
+			{Name: "b", Value: "2"},
+			nil,
+			{Name: "c", Value: "3"},
+			{Name: "d", Value: "4"},
+			{Name: "e", Value: "5"},
+		},
+	}
+
+	this.render(result)
+
+	this.So(this.response.Header()["Set-Cookie"], should.Resemble, []string{"a=1", "b=2", "c=3", "d=4", "e=5"})
+}
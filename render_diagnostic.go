@@ -1,15 +1,82 @@
 package detour
 
-import "net/http"
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
 
+// DiagnosticResult renders a plaintext error. The Message (and the dump of
+// non-canonical request headers, if requested) is only rendered when verbose
+// diagnostics are enabled (see SetVerboseDiagnostics and VerboseDiagnostics),
+// otherwise the status text is rendered in its place so as not to disclose
+// internal details to clients in production.
 type DiagnosticResult struct {
-	StatusCode int
+	StatusCode int // defaults to 500 (Internal Server Error)
 	Message    string
 	Header     http.Header
 
-	DumpNonCanonicalRequestHeaders bool // no-op
+	// DumpNonCanonicalRequestHeaders lists any request header keys that aren't
+	// in canonical form (and so won't be found by http.Header.Get).
+	DumpNonCanonicalRequestHeaders bool
 }
 
-func (this DiagnosticResult) Render(response http.ResponseWriter, _ *http.Request) {
-	http.Error(response, http.StatusText(this.StatusCode)+"\n", this.StatusCode)
+// SetVerboseDiagnostics sets the default for all handlers. It should only be called during initialization.
+func SetVerboseDiagnostics(enabled bool) {
+	defaultSettings.verboseDiagnostics = enabled
+}
+
+// VerboseDiagnostics overrides the default established by SetVerboseDiagnostics for a single handler.
+func VerboseDiagnostics(enabled bool) Option {
+	return func(this *actionHandler) {
+		this.settings = append(this.settings, func(settings *settings) { settings.verboseDiagnostics = enabled })
+	}
+}
+
+func (this DiagnosticResult) Render(response http.ResponseWriter, request *http.Request) {
+	statusCode := this.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusInternalServerError
+	}
+	copyHeaders(this.Header, response.Header())
+	http.Error(response, this.message(statusCode, request), statusCode)
+}
+
+func (this DiagnosticResult) message(statusCode int, request *http.Request) string {
+	if !currentSettings(request).verboseDiagnostics {
+		return http.StatusText(statusCode)
+	}
+
+	message := this.Message
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	if this.DumpNonCanonicalRequestHeaders && request != nil {
+		message += dumpNonCanonicalHeaders(request.Header)
+	}
+	return message
+}
+
+func dumpNonCanonicalHeaders(header http.Header) string {
+	var keys []string
+	for key := range header {
+		if key != http.CanonicalHeaderKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	builder.WriteString("\n\nNon-canonical request headers:\n")
+	for _, key := range keys {
+		builder.WriteString("\n- ")
+		builder.WriteString(key)
+		builder.WriteString(" (expected: ")
+		builder.WriteString(http.CanonicalHeaderKey(key))
+		builder.WriteString(")")
+	}
+	return builder.String()
 }
@@ -0,0 +1,58 @@
+package detour
+
+import "net/http"
+
+func (this *ResultFixture) TestDiagnosticResult_NotVerbose_StatusTextOnly() {
+	this.render(DiagnosticResult{
+		StatusCode:                     http.StatusBadRequest,
+		Message:                        "secret details",
+		Header:                         http.Header{"X-Trace": {"abc"}},
+		DumpNonCanonicalRequestHeaders: true,
+	})
+
+	this.assertStatusCode(http.StatusBadRequest)
+	this.assertContent("Bad Request")
+	this.assertHasHeader("X-Trace", "abc")
+	this.assertHasHeader(contentTypeHeader, "text/plain; charset=utf-8")
+}
+
+func (this *ResultFixture) TestDiagnosticResult_DefaultStatusCode() {
+	this.render(DiagnosticResult{})
+
+	this.assertStatusCode(http.StatusInternalServerError)
+	this.assertContent("Internal Server Error")
+}
+
+func (this *ResultFixture) TestDiagnosticResult_Verbose_MessageRendered() {
+	SetVerboseDiagnostics(true)
+	defer SetVerboseDiagnostics(false)
+
+	this.render(DiagnosticResult{StatusCode: http.StatusBadRequest, Message: "Details"})
+
+	this.assertStatusCode(http.StatusBadRequest)
+	this.assertContent("Details")
+}
+
+func (this *ResultFixture) TestDiagnosticResult_Verbose_NoMessage_StatusText() {
+	SetVerboseDiagnostics(true)
+	defer SetVerboseDiagnostics(false)
+
+	this.render(DiagnosticResult{StatusCode: http.StatusBadRequest})
+
+	this.assertContent("Bad Request")
+}
+
+func (this *ResultFixture) TestDiagnosticResult_Verbose_DumpNonCanonicalRequestHeaders() {
+	SetVerboseDiagnostics(true)
+	defer SetVerboseDiagnostics(false)
+	this.request.Header["x-api-key"] = []string{"1"}
+	this.request.Header["X-forwarded-for"] = []string{"2"}
+	this.request.Header.Set("X-Canonical", "3")
+
+	this.render(DiagnosticResult{StatusCode: http.StatusBadRequest, Message: "Details", DumpNonCanonicalRequestHeaders: true})
+
+	this.assertContent("Details\n\n" +
+		"Non-canonical request headers:\n\n" +
+		"- X-forwarded-for (expected: X-Forwarded-For)\n" +
+		"- x-api-key (expected: X-Api-Key)")
+}
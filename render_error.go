@@ -8,14 +8,16 @@ type ErrorResult struct {
 	Error2     error
 	Error3     error
 	Error4     error
+	Errors     []error // appended after Error1..Error4
 }
 
-func (this ErrorResult) Render(response http.ResponseWriter, _ *http.Request) {
+func (this ErrorResult) Render(response http.ResponseWriter, request *http.Request) {
 	var failures Errors
 	failures = failures.Append(this.Error1)
 	failures = failures.Append(this.Error2)
 	failures = failures.Append(this.Error3)
 	failures = failures.Append(this.Error4)
+	failures = appendAll(failures, this.Errors)
 
-	writeJSONResponse(response, this.StatusCode, failures, jsonContentType, "")
+	writeJSONResponse(response, request, this.StatusCode, failures, jsonContentType, "")
 }
@@ -30,3 +30,15 @@ func (this *ResultFixture) TestErrorResult_StatusCodeDefaultsTo200() {
 
 	this.assertStatusCode(http.StatusOK)
 }
+func (this *ResultFixture) TestErrorResult_Errors() {
+	result := ErrorResult{
+		StatusCode: 409,
+		Error1:     SimpleInputError("message1", "field1"),
+		Errors:     Errors{nil, SimpleInputError("message2", "field2"), nil},
+	}
+
+	this.render(result)
+
+	this.assertStatusCode(409)
+	this.assertContent(`[{"fields":["field1"],"message":"message1"},{"fields":["field2"],"message":"message2"}]`)
+}
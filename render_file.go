@@ -0,0 +1,125 @@
+package detour
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileResult sends a file to be downloaded (or displayed inline), with a
+// Content-Disposition header that carries the Name (UTF-8 names included),
+// a content type derived from the Name or the content itself, and support
+// for conditional and (when the content is seekable) range requests.
+type FileResult struct {
+	Name        string    // the file name suggested to the client (any directory is omitted)
+	Content     []byte    // takes precedence over the Reader
+	Reader      io.Reader // an io.ReadSeeker enables range requests
+	ModTime     time.Time // sets Last-Modified unless zero
+	ETag        string    // explicit entity tag, quoted if necessary
+	Inline      bool      // display in the browser rather than saving to disk
+	ContentType string    // when blank, derived from the extension of the Name or by sniffing
+}
+
+func (this FileResult) Render(response http.ResponseWriter, request *http.Request) {
+	headers := response.Header()
+	headers.Set(contentDispositionHeader, contentDisposition(this.Name, this.Inline))
+	if len(this.ETag) > 0 {
+		headers.Set(etagHeader, quoteETag(this.ETag))
+	}
+
+	if this.Content != nil {
+		this.serve(response, request, bytes.NewReader(this.Content))
+	} else if seeker, ok := this.Reader.(io.ReadSeeker); ok {
+		this.serve(response, request, seeker)
+	} else if this.Reader != nil {
+		this.stream(response)
+	} else {
+		this.serve(response, request, bytes.NewReader(nil))
+	}
+}
+
+func (this FileResult) serve(response http.ResponseWriter, request *http.Request, content io.ReadSeeker) {
+	writeContentType(response, this.ContentType)
+	http.ServeContent(response, request, this.Name, this.ModTime, content)
+}
+
+func (this FileResult) stream(response http.ResponseWriter) {
+	contentType := firstNonBlank(this.ContentType, mime.TypeByExtension(filepath.Ext(this.Name)))
+	reader := this.Reader
+	if len(contentType) == 0 {
+		sniffed := make([]byte, sniffLength)
+		n, _ := io.ReadFull(reader, sniffed)
+		contentType = http.DetectContentType(sniffed[:n])
+		reader = io.MultiReader(bytes.NewReader(sniffed[:n]), reader)
+	}
+	writeValidators(response, "", false, nil, this.ModTime)
+	writeContentTypeAndStatusCode(response, http.StatusOK, contentType)
+	_, _ = io.Copy(response, reader)
+}
+
+// contentDisposition formats the header according to RFC 6266, with an ASCII
+// fallback for the filename parameter and, for names which require it, the
+// UTF-8 filename* parameter as defined by RFC 5987.
+func contentDisposition(name string, inline bool) string {
+	disposition := "attachment"
+	if inline {
+		disposition = "inline"
+	}
+	if name = path.Base(strings.Replace(name, `\`, "/", -1)); name == "." || name == "/" {
+		return disposition
+	}
+
+	fallback := asciiFilename(name)
+	value := fmt.Sprintf(`%s; filename="%s"`, disposition, fallback)
+	if fallback != name {
+		value += "; filename*=UTF-8''" + encodeRFC5987(name)
+	}
+	return value
+}
+
+func asciiFilename(name string) string {
+	var builder strings.Builder
+	for _, character := range name {
+		switch {
+		case character < 0x20 || character >= 0x7f:
+			builder.WriteByte('_')
+		case character == '"' || character == '\\':
+			builder.WriteByte('\\')
+			builder.WriteRune(character)
+		default:
+			builder.WriteRune(character)
+		}
+	}
+	return builder.String()
+}
+
+func encodeRFC5987(value string) string {
+	var builder strings.Builder
+	for x := 0; x < len(value); x++ {
+		if character := value[x]; isRFC5987AttributeCharacter(character) {
+			builder.WriteByte(character)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", character)
+		}
+	}
+	return builder.String()
+}
+func isRFC5987AttributeCharacter(character byte) bool {
+	switch {
+	case 'a' <= character && character <= 'z', 'A' <= character && character <= 'Z', '0' <= character && character <= '9':
+		return true
+	default:
+		return strings.IndexByte("!#$&+-.^_`|~", character) >= 0
+	}
+}
+
+const (
+	contentDispositionHeader = "Content-Disposition"
+	sniffLength              = 512
+)
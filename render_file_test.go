@@ -0,0 +1,90 @@
+package detour
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/smartystreets/assertions/should"
+)
+
+func (this *ResultFixture) TestFileResult_Content() {
+	result := FileResult{Name: "report.csv", Content: []byte("a,b\n1,2\n")}
+
+	this.render(result)
+
+	this.assertStatusCode(http.StatusOK)
+	this.assertContent("a,b\n1,2")
+	this.assertHasHeader(contentDispositionHeader, `attachment; filename="report.csv"`)
+	this.assertHasHeader(contentTypeHeader, "text/csv; charset=utf-8")
+	this.assertHasHeader("Accept-Ranges", "bytes")
+}
+func (this *ResultFixture) TestFileResult_Inline_ExplicitContentType() {
+	result := FileResult{Name: "report", Content: []byte("{}"), Inline: true, ContentType: "application/json"}
+
+	this.render(result)
+
+	this.assertHasHeader(contentDispositionHeader, `inline; filename="report"`)
+	this.assertHasHeader(contentTypeHeader, "application/json")
+}
+func (this *ResultFixture) TestFileResult_ContentTypeSniffed() {
+	this.render(FileResult{Name: "unknown", Content: []byte("<html><body>hi</body></html>")})
+	this.assertHasHeader(contentTypeHeader, "text/html; charset=utf-8")
+}
+func (this *ResultFixture) TestFileResult_SeekableReader_Ranges() {
+	this.request.Header.Set("Range", "bytes=0-4")
+
+	this.render(FileResult{Name: "a.txt", Reader: strings.NewReader("Hello, World!")})
+
+	this.assertStatusCode(http.StatusPartialContent)
+	this.assertContent("Hello")
+}
+func (this *ResultFixture) TestFileResult_NotModifiedSince() {
+	modified := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	this.request.Header.Set(ifModifiedSinceHeader, modified.Format(http.TimeFormat))
+
+	this.render(FileResult{Name: "a.txt", Content: []byte("Hello"), ModTime: modified})
+
+	this.assertStatusCode(http.StatusNotModified)
+}
+func (this *ResultFixture) TestFileResult_ETag_IfNoneMatch() {
+	this.request.Header.Set(ifNoneMatchHeader, `"v1"`)
+
+	this.render(FileResult{Name: "a.txt", Content: []byte("Hello"), ETag: "v1"})
+
+	this.assertStatusCode(http.StatusNotModified)
+}
+func (this *ResultFixture) TestFileResult_StreamedReader() {
+	modified := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	reader := ioutil.NopCloser(strings.NewReader("%PDF-1.4 " + strings.Repeat("x", 1024)))
+
+	this.render(FileResult{Name: "document", Reader: reader, ModTime: modified})
+
+	this.assertStatusCode(http.StatusOK)
+	this.assertHasHeader(contentTypeHeader, "application/pdf")
+	this.assertHasHeader(lastModifiedHeader, "Thu, 02 Jan 2020 03:04:05 GMT")
+	this.So(this.response.Body.Len(), should.Equal, 1033)
+}
+func (this *ResultFixture) TestFileResult_StreamedReader_ContentTypeFromExtension() {
+	this.render(FileResult{Name: "notes.txt", Reader: ioutil.NopCloser(strings.NewReader("hi"))})
+
+	this.assertHasHeader(contentTypeHeader, "text/plain; charset=utf-8")
+	this.assertContent("hi")
+}
+func (this *ResultFixture) TestFileResult_NoContent() {
+	this.render(FileResult{Name: "empty.txt"})
+
+	this.assertStatusCode(http.StatusOK)
+	this.assertHasHeader("Content-Length", "0")
+}
+
+func (this *ResultFixture) TestContentDisposition() {
+	this.So(contentDisposition("", false), should.Equal, "attachment")
+	this.So(contentDisposition("dir/sub/a.txt", false), should.Equal, `attachment; filename="a.txt"`)
+	this.So(contentDisposition(`C:\dir\a.txt`, false), should.Equal, `attachment; filename="a.txt"`)
+	this.So(contentDisposition(`say "hi".txt`, true), should.Equal,
+		`inline; filename="say \"hi\".txt"; filename*=UTF-8''say%20%22hi%22.txt`)
+	this.So(contentDisposition("résumé €.pdf", false), should.Equal,
+		`attachment; filename="r_sum_ _.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9%20%E2%82%AC.pdf`)
+}
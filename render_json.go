@@ -1,6 +1,9 @@
 package detour
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 type JSONResult struct {
 	StatusCode  int
@@ -8,13 +11,18 @@ type JSONResult struct {
 	Content     interface{}
 	Indent      string
 	Header      http.Header
+
+	ETag         string    // explicit entity tag, quoted if necessary
+	GenerateETag bool      // derive a strong ETag from the serialized Content (ignored when ETag is set)
+	LastModified time.Time // omitted when zero
 }
 
-func (this JSONResult) Render(response http.ResponseWriter, _ *http.Request) {
+func (this JSONResult) Render(response http.ResponseWriter, request *http.Request) {
 	copyHeaders(this.Header, response.Header())
-	writeJSONResponse(response,
-		this.StatusCode,
-		this.Content,
-		firstNonBlank(this.ContentType, jsonContentType),
-		this.Indent)
+	writeContentType(response, firstNonBlank(this.ContentType, jsonContentType))
+	content, err := currentSettings(request).encodeJSON(this.Content, this.Indent)
+	if err == nil {
+		writeValidators(response, this.ETag, this.GenerateETag, content, this.LastModified)
+	}
+	writeResponse(response, this.StatusCode, content, err)
 }
@@ -1,6 +1,10 @@
 package detour
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/smartystreets/assertions/should"
+)
 
 func (this *ResultFixture) TestJSONResult() {
 	result := JSONResult{
@@ -73,3 +77,24 @@ func (this *ResultFixture) TestJSONResultHeadersCopiedToResponse() {
 	this.assertHasHeader("Key", "value")
 	this.assertHasHeader("Key", "already-added")
 }
+func (this *ResultFixture) TestJSONResult_GeneratedETagFromSerializedContent() {
+	result := JSONResult{
+		Content:      map[string]string{"key": "value"},
+		GenerateETag: true,
+	}
+
+	this.render(result)
+
+	this.So(this.response.Header().Get(etagHeader), should.Equal, generateETag([]byte(`{"key":"value"}`+"\n")))
+}
+func (this *ResultFixture) TestJSONResult_SerializationFailure_NoETag() {
+	result := JSONResult{
+		Content:      new(BadJSON),
+		ETag:         `W/"weak"`,
+		GenerateETag: true,
+	}
+
+	this.render(result)
+
+	this.So(this.response.Header().Get(etagHeader), should.BeBlank)
+}
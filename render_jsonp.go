@@ -2,35 +2,124 @@ package detour
 
 import (
 	"bytes"
-	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
 )
 
 type JSONPResult struct {
 	StatusCode  int
-	ContentType string
+	ContentType string // defaults to application/json; ignored when a callback is provided (application/javascript)
 	Content     interface{}
 	Indent      string
 	Header      http.Header
+
+	CallbackParameter string // the query string parameter naming the callback (see JSONPCallbackParameter)
+}
+
+// JSONPCallbackParameter names the query string parameter from which JSONPResult and
+// JSONBodyRenderer (unless they specify their own) read the callback. The default is "callback".
+func JSONPCallbackParameter(name string) Option {
+	return func(this *actionHandler) {
+		this.settings = append(this.settings, func(settings *settings) { settings.callbackParameter = name })
+	}
 }
 
 func (this JSONPResult) Render(response http.ResponseWriter, request *http.Request) {
 	copyHeaders(this.Header, response.Header())
 	writeContentType(response, firstNonBlank(this.ContentType, jsonContentType))
-	content, err := serializeJSON(this.Content, this.Indent)
-	content = wrapJSONP(content, callbackLabel(request))
-	writeResponse(response, this.StatusCode, content, err)
+	parameter := callbackParameter(request, this.CallbackParameter)
+	if content, ok := serializeJSONP(response, request, this.Content, this.Indent, parameter); ok {
+		writeContent(response, this.StatusCode, content)
+	}
 }
 
-func wrapJSONP(content []byte, label string) []byte {
+// serializeJSONP serializes the content, wrapped in a call to the callback provided by
+// the named query string parameter (if any). Upon failure (an invalid callback or content
+// that can't be serialized) the appropriate response is written and false is returned.
+func serializeJSONP(response http.ResponseWriter, request *http.Request, content interface{}, indent, parameter string) ([]byte, bool) {
+	var callback string
+	if len(parameter) > 0 {
+		// We don't call request.ParseForm in every case so using the URL.Query() is safer.
+		callback = request.URL.Query().Get(parameter)
+	}
+	if !isValidCallback(callback) {
+		writeInvalidCallback(response, request, parameter)
+		return nil, false
+	}
+
+	serialized, err := currentSettings(request).encodeJSON(content, indent)
+	if err != nil {
+		writeInternalServerError(response)
+		return nil, false
+	}
+	return wrapJSONP(response.Header(), serialized, callback), true
+}
+
+// wrapJSONP returns the (trimmed) content as a call to the callback (having set the
+// headers that accompany a JSONP response) or, if there's no callback, by itself.
+// The leading comment defends against content-sniffing attacks (ie. 'Rosetta Flash').
+func wrapJSONP(header http.Header, content []byte, callback string) []byte {
 	serialized := bytes.TrimSpace(content)
-	if len(label) > 0 {
-		serialized = []byte(fmt.Sprintf("%s(%s)", label, serialized))
+	if len(callback) == 0 {
+		return serialized
+	}
+	header.Set(contentTypeHeader, javascriptContentType)
+	header.Set(contentTypeOptionsHeader, "nosniff")
+
+	wrapped := make([]byte, 0, len(serialized)+len(callback)+len("/**/()"))
+	wrapped = append(wrapped, "/**/"...)
+	wrapped = append(wrapped, callback...)
+	wrapped = append(wrapped, '(')
+	wrapped = append(wrapped, serialized...)
+	return append(wrapped, ')')
+}
+
+func callbackParameter(request *http.Request, parameter string) string {
+	return firstNonBlank(parameter, currentSettings(request).callbackParameter, defaultCallbackParameter)
+}
+
+func writeInvalidCallback(response http.ResponseWriter, request *http.Request, parameter string) {
+	failures := Errors{SimpleInputError("Invalid callback", parameter)}
+	writeJSONResponse(response, request, http.StatusBadRequest, failures, jsonContentType, "")
+}
+
+// isValidCallback accepts (a blank callback or) a JavaScript identifier, optionally
+// followed by any number of dotted identifiers and/or numeric bracket indexes
+// (ie. "jQuery.callbacks[12].done"), so long as no identifier is a reserved word.
+func isValidCallback(callback string) bool {
+	if len(callback) == 0 {
+		return true
+	}
+	if len(callback) > maxCallbackLength || !callbackPattern.MatchString(callback) {
+		return false
 	}
-	return serialized
+	for _, identifier := range callbackIdentifiers.FindAllString(callback, -1) {
+		if reservedWords[identifier] {
+			return false
+		}
+	}
+	return true
 }
 
-func callbackLabel(request *http.Request) string {
-	// We don't call request.ParseForm in every case so using the URL.Query() is safer.
-	return request.URL.Query().Get("callback")
+const (
+	defaultCallbackParameter = "callback"
+	maxCallbackLength        = 128
+)
+
+var (
+	callbackPattern     = regexp.MustCompile(`^[A-Za-z_$][\w$]*(?:\.[A-Za-z_$][\w$]*|\[\d+\])*$`)
+	callbackIdentifiers = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+	reservedWords       = make(map[string]bool)
+)
+
+func init() {
+	for _, word := range strings.Fields(`
+		abstract arguments await boolean break byte case catch char class const continue
+		debugger default delete do double else enum eval export extends false final finally
+		float for function goto if implements import in instanceof int interface let long
+		native new null package private protected public return short static super switch
+		synchronized this throw throws transient true try typeof var void volatile while with yield`) {
+		reservedWords[word] = true
+	}
 }
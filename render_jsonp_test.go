@@ -1,6 +1,12 @@
 package detour
 
-import "net/http"
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/smartystreets/assertions/should"
+)
 
 func (this *ResultFixture) TestJSONPResult() {
 	this.setRequestURLCallback("maybe")
@@ -12,8 +18,9 @@ func (this *ResultFixture) TestJSONPResult() {
 	this.render(result)
 
 	this.assertStatusCode(123)
-	this.assertContent(`maybe({"key":"value"})`)
-	this.assertHasHeader(contentTypeHeader, jsonContentType)
+	this.assertContent(`/**/maybe({"key":"value"})`)
+	this.assertHasHeader(contentTypeHeader, javascriptContentType)
+	this.assertHasHeader(contentTypeOptionsHeader, "nosniff")
 }
 func (this *ResultFixture) TestJSONPResultIndented() {
 	this.setRequestURLCallback("maybe")
@@ -26,10 +33,10 @@ func (this *ResultFixture) TestJSONPResultIndented() {
 	this.render(result)
 
 	this.assertStatusCode(123)
-	this.assertContent(`maybe({
+	this.assertContent(`/**/maybe({
   "key": "value"
 })`)
-	this.assertHasHeader(contentTypeHeader, jsonContentType)
+	this.assertHasHeader(contentTypeHeader, javascriptContentType)
 }
 func (this *ResultFixture) TestJSONPResult_WithCustomContentType() {
 	this.setRequestURLCallback("maybe")
@@ -42,8 +49,19 @@ func (this *ResultFixture) TestJSONPResult_WithCustomContentType() {
 	this.render(result)
 
 	this.assertStatusCode(123)
-	this.assertContent(`maybe({"key":"value"})`)
-	this.assertHasHeader(contentTypeHeader, "application/custom-json")
+	this.assertContent(`/**/maybe({"key":"value"})`)
+	this.So(this.response.Header().Get(contentTypeHeader), should.Equal, javascriptContentType)
+	this.assertHasHeader(contentTypeOptionsHeader, "nosniff")
+}
+func (this *ResultFixture) TestJSONPResult_WithCustomContentType_NoCallback() {
+	result := JSONPResult{
+		ContentType: "application/custom-json",
+		Content:     map[string]string{"key": "value"},
+	}
+
+	this.render(result)
+
+	this.So(this.response.Header().Get(contentTypeHeader), should.Equal, "application/custom-json")
 }
 func (this *ResultFixture) TestJSONPResult_SerializationFailure_HTTP500WithErrorMessage() {
 	this.setRequestURLCallback("maybe")
@@ -92,3 +110,116 @@ func (this *ResultFixture) TestJSONPResultHeadersAddedToResponse() {
 	this.assertHasHeader("Key", "value")
 	this.assertHasHeader("Key", "already-added")
 }
+func (this *ResultFixture) TestJSONPResult_ExactBody() {
+	this.render(JSONPResult{Content: 1})
+	this.So(this.response.Body.String(), should.Equal, "1")
+
+	this.response = httptest.NewRecorder()
+	this.setRequestURLCallback("maybe")
+	this.render(JSONPResult{Content: 1})
+	this.So(this.response.Body.String(), should.Equal, "/**/maybe(1)")
+}
+func (this *ResultFixture) TestJSONPResult_NoCallback_NoJSONPHeaders() {
+	this.render(JSONPResult{Content: 1})
+
+	this.So(this.response.Header().Get(contentTypeOptionsHeader), should.BeBlank)
+}
+func (this *ResultFixture) TestJSONPResult_InvalidCallback_HTTP400() {
+	this.setRequestURLCallback("alert(document.cookie);maybe")
+	result := JSONPResult{
+		StatusCode: 123,
+		Content:    map[string]string{"key": "value"},
+	}
+
+	this.render(result)
+
+	this.assertStatusCode(http.StatusBadRequest)
+	this.assertHasHeader(contentTypeHeader, jsonContentType)
+	this.assertContent(`[{"fields":["callback"],"message":"Invalid callback"}]`)
+}
+func (this *ResultFixture) TestCallbackValidation() {
+	for _, callback := range []string{
+		"",
+		"maybe",
+		"$",
+		"_private",
+		"jQuery1234_5678",
+		"window.callbacks.done",
+		"callbacks[0]",
+		"jQuery.callbacks[12].done",
+		"$.fn._x1",
+		"newValue",
+		"thisOne",
+		strings.Repeat("a", maxCallbackLength),
+	} {
+		this.So(isValidCallback(callback), should.BeTrue)
+	}
+	for _, callback := range []string{
+		"1abc",
+		"alert(1)",
+		"a;b",
+		"a b",
+		"a.",
+		".a",
+		"a..b",
+		"a[]",
+		"a[b]",
+		"a['b']",
+		"a[-1]",
+		"a\u0028",
+		"<script>",
+		"a/**/",
+		"ünïcödé",
+		"new",
+		"eval",
+		"window.delete",
+		"a.this[0]",
+		strings.Repeat("a", maxCallbackLength+1),
+	} {
+		this.So(isValidCallback(callback), should.BeFalse)
+	}
+}
+func (this *ResultFixture) TestJSONPResult_CustomCallbackParameter() {
+	this.request = httptest.NewRequest("GET", "/?callback=ignored&jsonp=maybe", nil)
+
+	this.render(JSONPResult{Content: 1, CallbackParameter: "jsonp"})
+
+	this.assertContent(`/**/maybe(1)`)
+}
+func (this *ResultFixture) TestJSONPResult_CustomCallbackParameter_InvalidCallback() {
+	this.request = httptest.NewRequest("GET", "/?jsonp=alert(1)", nil)
+
+	this.render(JSONPResult{Content: 1, CallbackParameter: "jsonp"})
+
+	this.assertStatusCode(http.StatusBadRequest)
+	this.assertContent(`[{"fields":["jsonp"],"message":"Invalid callback"}]`)
+}
+func (this *ResultFixture) TestJSONPCallbackParameter_HandlerOption() {
+	this.request = httptest.NewRequest("GET", "/?callback=ignored&cb=maybe", nil)
+	handler := New(func() Renderer { return JSONPResult{Content: 1} }, JSONPCallbackParameter("cb"))
+
+	handler.ServeHTTP(this.response, this.request)
+
+	this.assertContent(`/**/maybe(1)`)
+}
+func (this *ResultFixture) TestJSONPCallbackParameter_RendererTakesPrecedenceOverHandlerOption() {
+	this.request = httptest.NewRequest("GET", "/?cb=ignored&jsonp=maybe", nil)
+	handler := New(func() Renderer {
+		return JSONBodyRenderer{Content: 1, JSONp: true, CallbackParameter: "jsonp"}
+	}, JSONPCallbackParameter("cb"))
+
+	handler.ServeHTTP(this.response, this.request)
+
+	this.assertContent(`/**/maybe(1)`)
+}
+func (this *ResultFixture) TestJSONP_SharedOutput() {
+	this.request = httptest.NewRequest("GET", "/?callback=maybe", nil)
+	result := httptest.NewRecorder()
+	body := httptest.NewRecorder()
+
+	JSONPResult{Content: []int{1}, Indent: " "}.Render(result, this.request)
+	JSONBodyRenderer{Content: []int{1}, Indent: " ", JSONp: true}.Render(body, this.request)
+
+	this.So(result.Body.String(), should.Equal, body.Body.String())
+	this.So(result.Header(), should.Resemble, body.Header())
+}
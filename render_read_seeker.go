@@ -0,0 +1,24 @@
+package detour
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// ReadSeekerResult serves the Content with support for byte-range requests (Range,
+// If-Range, multipart/byteranges, and 416 Range Not Satisfiable) and conditional
+// requests, exactly as http.ServeContent does.
+type ReadSeekerResult struct {
+	ContentType string // when blank, derived from the extension of the Name or by sniffing the Content
+	Name        string
+	ModTime     time.Time // sets Last-Modified unless zero
+	Content     io.ReadSeeker
+	Header      http.Header
+}
+
+func (this ReadSeekerResult) Render(response http.ResponseWriter, request *http.Request) {
+	copyHeaders(this.Header, response.Header())
+	writeContentType(response, this.ContentType)
+	http.ServeContent(response, request, this.Name, this.ModTime, this.Content)
+}
@@ -0,0 +1,66 @@
+package detour
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/smartystreets/assertions/should"
+)
+
+func (this *ResultFixture) TestReadSeekerResult_FullContent() {
+	modified := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	result := ReadSeekerResult{
+		Name:    "report.csv",
+		ModTime: modified,
+		Content: strings.NewReader("a,b,c\n1,2,3\n"),
+		Header:  http.Header{"Cache-Control": {"private"}},
+	}
+
+	this.render(result)
+
+	this.assertStatusCode(http.StatusOK)
+	this.assertContent("a,b,c\n1,2,3")
+	this.assertHasHeader("Accept-Ranges", "bytes")
+	this.assertHasHeader("Content-Length", "12")
+	this.assertHasHeader("Cache-Control", "private")
+	this.assertHasHeader(contentTypeHeader, "text/csv; charset=utf-8")
+	this.assertHasHeader(lastModifiedHeader, "Thu, 02 Jan 2020 03:04:05 GMT")
+}
+func (this *ResultFixture) TestReadSeekerResult_SingleRange() {
+	this.request.Header.Set("Range", "bytes=2-4")
+
+	this.render(ReadSeekerResult{ContentType: "text/plain", Content: strings.NewReader("0123456789")})
+
+	this.assertStatusCode(http.StatusPartialContent)
+	this.assertContent("234")
+	this.assertHasHeader("Content-Range", "bytes 2-4/10")
+	this.assertHasHeader(contentTypeHeader, "text/plain")
+}
+func (this *ResultFixture) TestReadSeekerResult_MultipleRanges() {
+	this.request.Header.Set("Range", "bytes=0-1,8-9")
+
+	this.render(ReadSeekerResult{ContentType: "text/plain", Content: strings.NewReader("0123456789")})
+
+	this.assertStatusCode(http.StatusPartialContent)
+	this.So(this.response.Header().Get(contentTypeHeader), should.StartWith, "multipart/byteranges; boundary=")
+	this.So(this.response.Body.String(), should.ContainSubstring, "Content-Range: bytes 8-9/10")
+}
+func (this *ResultFixture) TestReadSeekerResult_UnsatisfiableRange() {
+	this.request.Header.Set("Range", "bytes=20-30")
+
+	this.render(ReadSeekerResult{Content: strings.NewReader("0123456789")})
+
+	this.assertStatusCode(http.StatusRequestedRangeNotSatisfiable)
+	this.assertHasHeader("Content-Range", "bytes */10")
+}
+func (this *ResultFixture) TestReadSeekerResult_StaleIfRange_FullContent() {
+	this.request.Header.Set("Range", "bytes=2-4")
+	this.request.Header.Set("If-Range", `"old"`)
+	this.response.Header().Set(etagHeader, `"new"`)
+
+	this.render(ReadSeekerResult{Content: strings.NewReader("0123456789")})
+
+	this.assertStatusCode(http.StatusOK)
+	this.assertContent("0123456789")
+}
@@ -1,12 +1,77 @@
 package detour
 
-import "net/http"
+import (
+	"log"
+	"net/http"
+	"net/url"
+)
 
 type RedirectResult struct {
-	Location   string
-	StatusCode int
+	Location   string // may be relative to the URL of the current request
+	StatusCode int    // must be 3xx (or a 500 is logged and rendered instead); defaults to 302 (Found)
+
+	PreserveQuery bool       // carry the query string of the current request into the Location
+	Query         url.Values // merged into the query string of the Location (taking precedence)
+}
+
+func MovedPermanently(location string) RedirectResult {
+	return RedirectResult{Location: location, StatusCode: http.StatusMovedPermanently}
+}
+func Found(location string) RedirectResult {
+	return RedirectResult{Location: location, StatusCode: http.StatusFound}
+}
+func SeeOther(location string) RedirectResult {
+	return RedirectResult{Location: location, StatusCode: http.StatusSeeOther}
+}
+func TemporaryRedirect(location string) RedirectResult {
+	return RedirectResult{Location: location, StatusCode: http.StatusTemporaryRedirect}
+}
+func PermanentRedirect(location string) RedirectResult {
+	return RedirectResult{Location: location, StatusCode: http.StatusPermanentRedirect}
 }
 
 func (this RedirectResult) Render(response http.ResponseWriter, request *http.Request) {
-	http.Redirect(response, request, this.Location, this.StatusCode)
+	statusCode := this.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusFound
+	}
+	if !isRedirect(statusCode) {
+		log.Printf("detour: redirects require a 3xx status code, not [%d], for [%s %s]", statusCode, request.Method, request.URL.Path)
+		StatusCodeResult{StatusCode: http.StatusInternalServerError, Message: http.StatusText(http.StatusInternalServerError)}.Render(response, request)
+		return
+	}
+	http.Redirect(response, request, this.location(request), statusCode)
+}
+
+func (this RedirectResult) location(request *http.Request) string {
+	location, err := url.Parse(this.Location)
+	if err != nil {
+		return this.Location
+	}
+	if request.URL != nil {
+		location = request.URL.ResolveReference(location)
+	}
+
+	if !this.PreserveQuery && len(this.Query) == 0 {
+		return location.String()
+	}
+
+	query := make(url.Values)
+	if this.PreserveQuery && request.URL != nil {
+		mergeQuery(query, request.URL.Query())
+	}
+	mergeQuery(query, location.Query())
+	mergeQuery(query, this.Query)
+	location.RawQuery = query.Encode()
+	return location.String()
+}
+
+func mergeQuery(destination, source url.Values) {
+	for key, values := range source {
+		destination[key] = values
+	}
+}
+
+func isRedirect(statusCode int) bool {
+	return statusCode >= 300 && statusCode < 400
 }
@@ -1,7 +1,12 @@
 package detour
 
 import (
+	"bytes"
+	"log"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
 
 	"github.com/smartystreets/assertions/should"
 )
@@ -18,3 +23,76 @@ func (this *ResultFixture) TestRedirectResult() {
 	this.So(this.response.Header().Get("Location"), should.Equal, "http://www.google.com")
 	this.assertContent(`<a href="http://www.google.com">Moved Permanently</a>.`)
 }
+
+func (this *ResultFixture) TestRedirectResult_DefaultStatusCode_Found() {
+	this.render(RedirectResult{Location: "/somewhere"})
+
+	this.assertStatusCode(http.StatusFound)
+	this.So(this.response.Header().Get("Location"), should.Equal, "/somewhere")
+}
+
+func (this *ResultFixture) TestRedirectResult_Non3xxStatusCode_HTTP500() {
+	logged := new(bytes.Buffer)
+	log.SetOutput(logged)
+	defer log.SetOutput(os.Stderr)
+
+	this.render(RedirectResult{Location: "/", StatusCode: http.StatusCreated})
+
+	this.assertStatusCode(http.StatusInternalServerError)
+	this.So(this.response.Header().Get("Location"), should.BeBlank)
+	this.So(logged.String(), should.ContainSubstring, "detour: redirects require a 3xx status code, not [201], for [GET /]")
+}
+
+func (this *ResultFixture) TestRedirectHelpers() {
+	this.So(MovedPermanently("/a").StatusCode, should.Equal, http.StatusMovedPermanently)
+	this.So(Found("/a").StatusCode, should.Equal, http.StatusFound)
+	this.So(SeeOther("/a").StatusCode, should.Equal, http.StatusSeeOther)
+	this.So(TemporaryRedirect("/a").StatusCode, should.Equal, http.StatusTemporaryRedirect)
+	this.So(PermanentRedirect("/a").StatusCode, should.Equal, http.StatusPermanentRedirect)
+	this.So(PermanentRedirect("/a").Location, should.Equal, "/a")
+}
+
+func (this *ResultFixture) TestRedirectResult_PermanentRedirect() {
+	this.request = httptest.NewRequest("POST", "/", nil)
+
+	this.render(PermanentRedirect("/moved"))
+
+	this.assertStatusCode(http.StatusPermanentRedirect)
+	this.So(this.response.Header().Get("Location"), should.Equal, "/moved")
+}
+
+func (this *ResultFixture) TestRedirectResult_RelativeLocation_ResolvedAgainstRequest() {
+	this.request = httptest.NewRequest("GET", "/users/123/edit?x=1", nil)
+
+	this.render(SeeOther("../456"))
+
+	this.So(this.response.Header().Get("Location"), should.Equal, "/users/456")
+}
+
+func (this *ResultFixture) TestRedirectResult_PreserveQuery() {
+	this.request = httptest.NewRequest("GET", "/old?a=1&b=2", nil)
+
+	this.render(RedirectResult{Location: "/new?b=3", PreserveQuery: true})
+
+	this.So(this.response.Header().Get("Location"), should.Equal, "/new?a=1&b=3")
+}
+
+func (this *ResultFixture) TestRedirectResult_QueryMerged_TakesPrecedence() {
+	this.request = httptest.NewRequest("GET", "/old?a=1", nil)
+
+	this.render(RedirectResult{
+		Location:      "https://example.com/new?b=2&c=3",
+		PreserveQuery: true,
+		Query:         url.Values{"c": {"4"}, "d": {"5", "6"}},
+	})
+
+	this.So(this.response.Header().Get("Location"), should.Equal, "https://example.com/new?a=1&b=2&c=4&d=5&d=6")
+}
+
+func (this *ResultFixture) TestRedirectResult_QueryIgnoredWithoutPreserveQuery() {
+	this.request = httptest.NewRequest("GET", "/old?a=1", nil)
+
+	this.render(RedirectResult{Location: "/new"})
+
+	this.So(this.response.Header().Get("Location"), should.Equal, "/new")
+}
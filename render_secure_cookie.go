@@ -0,0 +1,26 @@
+package detour
+
+import "net/http"
+
+// SecureCookieResult sets cookies whose values are signed (and optionally
+// encrypted) by the Codec. Nil cookies are skipped.
+type SecureCookieResult struct {
+	Codec   *CookieCodec
+	Cookies []*http.Cookie
+}
+
+func (this SecureCookieResult) Render(response http.ResponseWriter, _ *http.Request) {
+	for _, cookie := range this.Cookies {
+		if cookie == nil {
+			continue
+		}
+		encoded := *cookie
+		value, err := this.Codec.Encode(cookie.Name, cookie.Value)
+		if err != nil {
+			writeInternalServerError(response)
+			return
+		}
+		encoded.Value = value
+		http.SetCookie(response, &encoded)
+	}
+}
@@ -0,0 +1,44 @@
+package detour
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/smartystreets/assertions/should"
+)
+
+func (this *ResultFixture) TestSecureCookieResult() {
+	codec, _ := NewCookieCodec(CookieKey{Signing: []byte("key")})
+	result := SecureCookieResult{
+		Codec: codec,
+		Cookies: []*http.Cookie{
+			{Name: "session", Value: "user-42", HttpOnly: true},
+			nil,
+			{Name: "theme", Value: "dark"},
+		},
+	}
+
+	this.render(result)
+
+	cookies := this.response.Result().Cookies()
+	this.So(cookies, should.HaveLength, 2)
+	this.So(cookies[0].HttpOnly, should.BeTrue)
+	session, err := codec.Decode("session", cookies[0].Value)
+	this.So(err, should.BeNil)
+	this.So(session, should.Equal, "user-42")
+	theme, err := codec.Decode("theme", cookies[1].Value)
+	this.So(err, should.BeNil)
+	this.So(theme, should.Equal, "dark")
+	this.So(result.Cookies[0].Value, should.Equal, "user-42") // not modified
+}
+
+func (this *ResultFixture) TestSecureCookieResult_MissingCodec_HTTP500() {
+	for _, codec := range []*CookieCodec{nil, {}} {
+		this.response = httptest.NewRecorder()
+
+		this.render(SecureCookieResult{Codec: codec, Cookies: []*http.Cookie{{Name: "session", Value: "user-42"}}})
+
+		this.assertStatusCode(http.StatusInternalServerError)
+		this.So(this.response.Result().Cookies(), should.BeEmpty)
+	}
+}
@@ -0,0 +1,48 @@
+package detour
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// TemplateResult renders a page of the Templates (or of the registered Templates,
+// see RegisterTemplates). The page is executed to completion before anything is
+// written so that a failure results in a clean 500 rather than a partial page.
+type TemplateResult struct {
+	StatusCode  int
+	ContentType string // defaults to text/html; charset=utf-8
+	Name        string // the file name of the page
+	Data        interface{}
+	Templates   *Templates
+}
+
+func (this TemplateResult) Render(response http.ResponseWriter, request *http.Request) {
+	content, err := this.execute()
+	if err != nil {
+		log.Printf("detour: template [%s] failed for [%s %s]: %s", this.Name, request.Method, request.URL.Path, err)
+		writeContentTypeAndStatusCode(response, http.StatusInternalServerError, plaintextContentType)
+		response.Write([]byte(http.StatusText(http.StatusInternalServerError)))
+		return
+	}
+
+	writeContentTypeAndStatusCode(response, this.StatusCode, firstNonBlank(this.ContentType, htmlContentType))
+	response.Write(content)
+}
+
+func (this TemplateResult) execute() ([]byte, error) {
+	templates := this.Templates
+	if templates == nil {
+		templates = registeredTemplates
+	}
+	if templates == nil {
+		return nil, errNoTemplates
+	}
+
+	buffer := new(bytes.Buffer)
+	err := templates.Execute(buffer, this.Name, this.Data)
+	return buffer.Bytes(), err
+}
+
+var errNoTemplates = errors.New("no templates registered")
@@ -0,0 +1,46 @@
+package detour
+
+import (
+	"net/http"
+)
+
+func (this *ResultFixture) TestTemplateResult() {
+	templates := &Templates{Files: FakeTemplateFiles{"page.html": "<p>{{.}}</p>"}}
+
+	this.render(TemplateResult{StatusCode: http.StatusAccepted, Name: "page.html", Data: "hi", Templates: templates})
+
+	this.assertStatusCode(http.StatusAccepted)
+	this.assertContent("<p>hi</p>")
+	this.assertHasHeader(contentTypeHeader, htmlContentType)
+}
+func (this *ResultFixture) TestTemplateResult_CustomContentType() {
+	templates := &Templates{Files: FakeTemplateFiles{"page.xml": "<p>{{.}}</p>"}}
+
+	this.render(TemplateResult{Name: "page.xml", ContentType: "application/xml", Templates: templates})
+
+	this.assertStatusCode(http.StatusOK)
+	this.assertHasHeader(contentTypeHeader, "application/xml")
+}
+func (this *ResultFixture) TestTemplateResult_RegisteredTemplates() {
+	RegisterTemplates(&Templates{Files: FakeTemplateFiles{"page.html": "registered"}})
+	defer RegisterTemplates(nil)
+
+	this.render(TemplateResult{Name: "page.html"})
+
+	this.assertContent("registered")
+}
+func (this *ResultFixture) TestTemplateResult_NoTemplates_HTTP500() {
+	this.render(TemplateResult{Name: "page.html"})
+
+	this.assertStatusCode(http.StatusInternalServerError)
+	this.assertContent("Internal Server Error")
+}
+func (this *ResultFixture) TestTemplateResult_ExecutionFailure_CleanHTTP500() {
+	templates := &Templates{Files: FakeTemplateFiles{"page.html": "partial output {{.Missing}}"}}
+
+	this.render(TemplateResult{Name: "page.html", Data: 42, Templates: templates})
+
+	this.assertStatusCode(http.StatusInternalServerError)
+	this.assertContent("Internal Server Error")
+	this.assertHasHeader(contentTypeHeader, plaintextContentType)
+}
@@ -3,18 +3,25 @@ package detour
 import "net/http"
 
 type ValidationResult struct {
-	Failure1 error
-	Failure2 error
-	Failure3 error
-	Failure4 error
+	Failure1   error
+	Failure2   error
+	Failure3   error
+	Failure4   error
+	Failures   []error // appended after Failure1..Failure4
+	StatusCode int     // defaults to 422 (Unprocessable Entity)
 }
 
-func (this ValidationResult) Render(response http.ResponseWriter, _ *http.Request) {
+func (this ValidationResult) Render(response http.ResponseWriter, request *http.Request) {
 	var failures Errors
 	failures = failures.Append(this.Failure1)
 	failures = failures.Append(this.Failure2)
 	failures = failures.Append(this.Failure3)
 	failures = failures.Append(this.Failure4)
+	failures = appendAll(failures, this.Failures)
 
-	writeJSONResponse(response, http.StatusUnprocessableEntity, failures, jsonContentType, "")
+	statusCode := this.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusUnprocessableEntity
+	}
+	writeJSONResponse(response, request, statusCode, failures, jsonContentType, "")
 }
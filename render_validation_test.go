@@ -25,3 +25,22 @@ func (this *ResultFixture) TestValidationResult_SerializationFailure_HTTP500With
 	this.assertHasHeader(contentTypeHeader, jsonContentType)
 	this.assertContent(`[{"fields":["HTTP Response"],"message":"Marshal failure"}]`)
 }
+func (this *ResultFixture) TestValidationResult_FailuresAndCustomStatusCode() {
+	result := ValidationResult{
+		StatusCode: 400,
+		Failures: []error{
+			SimpleInputError("message1", "field1"),
+			nil,
+			SimpleInputError("message2", "field2"),
+			SimpleInputError("message3", "field3"),
+			SimpleInputError("message4", "field4"),
+			SimpleInputError("message5", "field5"),
+		},
+	}
+
+	this.render(result)
+
+	this.assertStatusCode(400)
+	this.assertContent(`[{"fields":["field1"],"message":"message1"},{"fields":["field2"],"message":"message2"},` +
+		`{"fields":["field3"],"message":"message3"},{"fields":["field4"],"message":"message4"},{"fields":["field5"],"message":"message5"}]`)
+}
@@ -1,7 +1,6 @@
 package detour
 
 import (
-	"encoding/json"
 	"encoding/xml"
 	"io"
 	"net/http"
@@ -94,10 +93,19 @@ func (this CookieRenderer) Render(response http.ResponseWriter, _ *http.Request)
 
 /* ------------------------------------------------------------------------- */
 
+// RedirectRenderer redirects with the status code previously written to the response
+// (ie. by a StatusCodeRenderer) if it's a 3xx and the response makes it available via
+// a StatusCode method (as detour's own buffered response does), or 302 (Found) otherwise.
 type RedirectRenderer string
 
 func (this RedirectRenderer) Render(response http.ResponseWriter, request *http.Request) {
-	http.Redirect(response, request, string(this), response.(*responseBuffer).StatusCode())
+	http.Redirect(response, request, string(this), redirectStatusCode(response))
+}
+func redirectStatusCode(response http.ResponseWriter) int {
+	if written, ok := response.(interface{ StatusCode() int }); ok && isRedirect(written.StatusCode()) {
+		return written.StatusCode()
+	}
+	return http.StatusFound
 }
 
 /* ------------------------------------------------------------------------- */
@@ -134,34 +142,24 @@ func (this XMLBodyRenderer) Render(response http.ResponseWriter, _ *http.Request
 
 /* ------------------------------------------------------------------------- */
 
+// JSONBodyRenderer (like JSONPResult) writes the Content as serialized by json.Marshal
+// (ie. without a trailing newline, regardless of JSONEncoding.OmitTrailingNewline).
 type JSONBodyRenderer struct {
 	Content interface{}
 	Indent  string
 	JSONp   bool
+
+	CallbackParameter string // see JSONPCallbackParameter
 }
 
 func (this JSONBodyRenderer) Render(response http.ResponseWriter, request *http.Request) {
-	callback := request.URL.Query().Get("callback")
-	canRenderJSONp := this.JSONp && callback != ""
-
-	if canRenderJSONp {
-		_, _ = io.WriteString(response, callback)
-		_, _ = io.WriteString(response, "(")
+	var parameter string
+	if this.JSONp {
+		parameter = callbackParameter(request, this.CallbackParameter)
 	}
-
-	_, _ = response.Write(this.renderContent())
-
-	if canRenderJSONp {
-		_, _ = io.WriteString(response, ")")
-	}
-}
-func (this JSONBodyRenderer) renderContent() (content []byte) {
-	if this.Indent != "" {
-		content, _ = json.MarshalIndent(this.Content, "", this.Indent)
-	} else {
-		content, _ = json.Marshal(this.Content)
+	if content, ok := serializeJSONP(response, request, this.Content, this.Indent, parameter); ok {
+		_, _ = response.Write(content)
 	}
-	return content
 }
 
 /* ------------------------------------------------------------------------- */
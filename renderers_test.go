@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -31,7 +32,7 @@ func (this *ResponsesFixture) render(renderers ...Renderer) {
 	buffer := newResponseBuffer()
 	renderer := CompoundRenderer(renderers)
 	renderer.Render(buffer, this.request)
-	buffer.flush(this.response)
+	buffer.flush(this.response, this.request)
 	all, _ := ioutil.ReadAll(this.response.Result().Body)
 	this.body = string(all)
 }
@@ -50,7 +51,10 @@ func (this *ResponsesFixture) assertHeaders(pairs ...string) {
 	}
 }
 func (this *ResponsesFixture) assertNoResponseHeaders() {
-	this.So(this.response.Result().Header, should.BeEmpty)
+	// flush always provides the Content-Length of the buffered body
+	this.So(this.response.Result().Header, should.Resemble, http.Header{
+		"Content-Length": []string{strconv.Itoa(len(this.body))},
+	})
 }
 func (this *ResponsesFixture) assertBlankBody() {
 	this.So(this.body, should.BeBlank)
@@ -137,6 +141,16 @@ func (this *ResponsesFixture) TestRedirect() {
 	this.assertHeaders("Location", "https://smartystreets.com/redirect")
 	this.assertBody(`<a href="https://smartystreets.com/redirect">Temporary Redirect</a>.`)
 }
+func (this *ResponsesFixture) TestRedirect_NoRedirectStatusCode_Found() {
+	this.render(RedirectRenderer("/redirect"))
+	this.assertStatusCode(http.StatusFound)
+	this.assertHeaders("Location", "/redirect")
+}
+func (this *ResponsesFixture) TestRedirect_ArbitraryResponseWriter() {
+	this.So(func() { RedirectRenderer("/redirect").Render(this.response, this.request) }, should.NotPanic)
+	this.assertStatusCode(http.StatusFound)
+	this.assertHeaders("Location", "/redirect")
+}
 func (this *ResponsesFixture) TestBytesBodyRenderer() {
 	this.render(BytesBodyRenderer("Hello, world!"))
 	this.assertStatusOK()
@@ -167,6 +181,14 @@ func (this *ResponsesFixture) TestJSONBodyRenderer() {
 	this.assertNoResponseHeaders()
 	this.assertBody("[1,2,3]")
 }
+func (this *ResponsesFixture) TestJSONBodyRenderer_NoTrailingNewline() {
+	this.render(JSONBodyRenderer{Content: []int{1, 2, 3}})
+	this.So(this.body, should.Equal, "[1,2,3]")
+
+	this.Setup()
+	this.render(JSONBodyRenderer{Content: []int{1}, Indent: "  "})
+	this.So(this.body, should.Equal, "[\n  1\n]")
+}
 func (this *ResponsesFixture) TestJSONBodyRenderer_Indentation() {
 	this.render(JSONBodyRenderer{Content: []int{1, 2, 3}, Indent: "  "})
 	this.assertStatusOK()
@@ -176,14 +198,16 @@ func (this *ResponsesFixture) TestJSONBodyRenderer_Indentation() {
 func (this *ResponsesFixture) TestJSONBodyRenderer_JSONP() {
 	this.render(JSONBodyRenderer{Content: []int{1, 2, 3}, JSONp: true})
 	this.assertStatusOK()
-	this.assertNoResponseHeaders()
-	this.assertBody("hello([1,2,3])")
+	this.assertHeaders(
+		contentTypeHeader, javascriptContentType,
+		contentTypeOptionsHeader, "nosniff",
+	)
+	this.assertBody("/**/hello([1,2,3])")
 }
 func (this *ResponsesFixture) TestJSONBodyRenderer_JSONP_Indent() {
 	this.render(JSONBodyRenderer{Content: []int{1, 2, 3}, JSONp: true, Indent: "  "})
 	this.assertStatusOK()
-	this.assertNoResponseHeaders()
-	this.assertBody("hello([\n  1,\n  2,\n  3\n])")
+	this.assertBody("/**/hello([\n  1,\n  2,\n  3\n])")
 }
 func (this *ResponsesFixture) TestJSONBodyRenderer_JSONP_NoCallback() {
 	query := this.request.URL.Query()
@@ -196,6 +220,35 @@ func (this *ResponsesFixture) TestJSONBodyRenderer_JSONP_NoCallback() {
 	this.assertNoResponseHeaders()
 	this.assertBody("[1,2,3]")
 }
+func (this *ResponsesFixture) TestJSONBodyRenderer_SerializationFailure_HTTP500WithErrorMessage() {
+	this.render(JSONBodyRenderer{Content: new(BadJSON)})
+	this.assertStatusCode(http.StatusInternalServerError)
+	this.assertBody(`[{"fields":["HTTP Response"],"message":"Marshal failure"}]`)
+}
+func (this *ResponsesFixture) TestJSONBodyRenderer_JSONEncoding() {
+	SetJSONEncoding(JSONEncoding{DisableHTMLEscaping: true})
+	defer SetJSONEncoding(JSONEncoding{})
+
+	this.render(JSONBodyRenderer{Content: "<&>"})
+
+	this.So(this.body, should.Equal, `"<&>"`)
+}
+func (this *ResponsesFixture) TestJSONBodyRenderer_JSONP_InvalidCallback() {
+	this.request = httptest.NewRequest(http.MethodGet, "/?callback=alert(1)", nil)
+
+	this.render(JSONBodyRenderer{Content: []int{1, 2, 3}, JSONp: true})
+
+	this.assertStatusCode(http.StatusBadRequest)
+	this.assertBody(`[{"fields":["callback"],"message":"Invalid callback"}]`)
+}
+func (this *ResponsesFixture) TestJSONBodyRenderer_InvalidCallbackIgnoredWithoutJSONP() {
+	this.request = httptest.NewRequest(http.MethodGet, "/?callback=alert(1)", nil)
+
+	this.render(JSONBodyRenderer{Content: []int{1, 2, 3}})
+
+	this.assertStatusOK()
+	this.assertBody("[1,2,3]")
+}
 func (this *ResponsesFixture) TestIfElseRendering_True() {
 	this.render(IfElseRenderer(
 		true,
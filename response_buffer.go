@@ -2,14 +2,22 @@ package detour
 
 import (
 	"bytes"
-	"io"
+	"log"
 	"net/http"
+	"os"
+	"strconv"
 )
 
 type responseBuffer struct {
 	statusCode int
 	headers    http.Header
 	body       *bytes.Buffer
+	scratch    *bytes.Buffer
+
+	spillThreshold int      // see SpillResponses
+	spill          *os.File // nil until the body exceeds the spillThreshold
+	spilled        int64
+	spillErr       error
 }
 
 func newResponseBuffer() *responseBuffer {
@@ -18,22 +26,84 @@ func newResponseBuffer() *responseBuffer {
 	return buffer
 }
 
-func (this *responseBuffer) StatusCode() int             { return this.statusCode }
-func (this *responseBuffer) Header() http.Header         { return this.headers }
-func (this *responseBuffer) Write(p []byte) (int, error) { return this.body.Write(p) }
-func (this *responseBuffer) WriteHeader(statusCode int)  { this.statusCode = statusCode }
+func (this *responseBuffer) StatusCode() int            { return this.statusCode }
+func (this *responseBuffer) Header() http.Header        { return this.headers }
+func (this *responseBuffer) WriteHeader(statusCode int) { this.statusCode = statusCode }
+func (this *responseBuffer) Write(p []byte) (int, error) {
+	if this.spillErr != nil {
+		return 0, this.spillErr
+	}
+	if this.spill != nil || (this.spillThreshold > 0 && this.body.Len()+len(p) > this.spillThreshold) {
+		return this.spillWrite(p)
+	}
+	return this.body.Write(p)
+}
 
-func (this *responseBuffer) flush(response http.ResponseWriter) {
+func (this *responseBuffer) flush(response http.ResponseWriter, request *http.Request) {
+	if this.spillErr != nil {
+		this.failedSpill(response, request)
+		this.initialize()
+		return
+	}
+	this.prepareBodyHeaders(request)
 	copyHeaders(this.headers, response.Header())
 	response.WriteHeader(this.statusCode)
-	_, _ = io.Copy(response, this.body)
+	if request.Method != http.MethodHead {
+		this.writeBody(response)
+	}
 	this.initialize()
 }
+
+// prepareBodyHeaders sets an accurate Content-Length (unless already provided or
+// the body is chunked) and discards any body written to a response that must not
+// have one. The Content-Length of a response to a HEAD request is the length of
+// the body which would have been written in response to the equivalent GET.
+func (this *responseBuffer) prepareBodyHeaders(request *http.Request) {
+	if !bodyAllowed(this.statusCode) {
+		if this.length() > 0 {
+			log.Printf("detour: discarded body (%d bytes) written to a [%d] response for [%s %s]",
+				this.length(), this.statusCode, request.Method, request.URL.Path)
+			this.discardBody()
+		}
+		if this.statusCode != http.StatusNotModified {
+			this.headers.Del(contentLengthHeader)
+		}
+		return
+	}
+
+	if len(this.headers.Get(contentLengthHeader)) > 0 || len(this.headers.Get(transferEncodingHeader)) > 0 {
+		return
+	}
+	this.headers.Set(contentLengthHeader, strconv.FormatInt(this.length(), 10))
+}
+
+func bodyAllowed(statusCode int) bool {
+	if statusCode >= 100 && statusCode < 200 {
+		return false
+	}
+	return statusCode != http.StatusNoContent && statusCode != http.StatusNotModified
+}
+
+// notModified discards the body and the headers that describe it, leaving the
+// validators (ETag, etc.) and caching headers of the response intact.
+func (this *responseBuffer) notModified() {
+	this.statusCode = http.StatusNotModified
+	this.discardBody()
+	this.headers.Del(contentTypeHeader)
+	this.headers.Del(contentLengthHeader)
+	this.headers.Del(contentEncodingHeader)
+	if len(this.headers.Get(etagHeader)) > 0 {
+		this.headers.Del(lastModifiedHeader)
+	}
+}
 func copyHeaders(source, destination http.Header) {
 	for key, value := range source {
 		destination[key] = append(destination[key], value...)
 	}
 }
+func (this *responseBuffer) capacity() int {
+	return this.body.Cap() + this.scratch.Cap()
+}
 func (this *responseBuffer) initialize() {
 	this.initializeStatusCode()
 	this.initializeHeaders()
@@ -45,9 +115,13 @@ func (this *responseBuffer) initializeStatusCode() {
 func (this *responseBuffer) initializeBody() {
 	if this.body == nil {
 		this.body = new(bytes.Buffer)
+		this.scratch = new(bytes.Buffer)
 	} else {
 		this.body.Reset()
+		this.scratch.Reset()
 	}
+	this.removeSpill()
+	this.spillThreshold = 0
 }
 func (this *responseBuffer) initializeHeaders() {
 	if this.headers == nil {
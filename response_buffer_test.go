@@ -0,0 +1,111 @@
+package detour
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestResponseBufferFixture(t *testing.T) {
+	gunit.Run(new(ResponseBufferFixture), t)
+}
+
+type ResponseBufferFixture struct {
+	*gunit.Fixture
+
+	request  *http.Request
+	response *httptest.ResponseRecorder
+	logged   *bytes.Buffer
+}
+
+func (this *ResponseBufferFixture) Setup() {
+	this.request = httptest.NewRequest("GET", "/path", nil)
+	this.response = httptest.NewRecorder()
+	this.logged = new(bytes.Buffer)
+	log.SetOutput(this.logged)
+}
+func (this *ResponseBufferFixture) Teardown() {
+	log.SetOutput(os.Stderr)
+}
+
+func (this *ResponseBufferFixture) serve(result Renderer, options ...Option) {
+	New(func() Renderer { return result }, options...).ServeHTTP(this.response, this.request)
+}
+
+func (this *ResponseBufferFixture) TestContentLengthOfBufferedBody() {
+	this.serve(ContentResult{Content: "Hello, World!"})
+
+	this.So(this.response.Header().Get(contentLengthHeader), should.Equal, "13")
+	this.So(this.response.Body.String(), should.Equal, "Hello, World!")
+}
+
+func (this *ResponseBufferFixture) TestContentLengthOfCompressedBody() {
+	this.request.Header.Set(acceptEncodingHeader, "gzip")
+
+	this.serve(ContentResult{Content: strings.Repeat("a", 2048)}, CompressResponses(1))
+
+	this.So(this.response.Header().Get(contentLengthHeader), should.Equal, strconv.Itoa(this.response.Body.Len()))
+	this.So(this.response.Body.Len(), should.BeLessThan, 2048)
+}
+
+func (this *ResponseBufferFixture) TestRendererProvidedContentLengthKept() {
+	this.serve(CompoundRenderer{SetHeaderPairsRenderer{contentLengthHeader, "5"}, StringBodyRenderer("Hello")})
+	this.So(this.response.Header()[contentLengthHeader], should.Resemble, []string{"5"})
+}
+
+func (this *ResponseBufferFixture) TestTransferEncodingRespected() {
+	this.serve(CompoundRenderer{SetHeaderPairsRenderer{transferEncodingHeader, "chunked"}, StringBodyRenderer("Hello")})
+
+	this.So(this.response.Header().Get(contentLengthHeader), should.BeBlank)
+	this.So(this.response.Body.String(), should.Equal, "Hello")
+}
+
+func (this *ResponseBufferFixture) TestHEAD_HeadersAndLengthWithoutBody() {
+	this.request.Method = http.MethodHead
+
+	this.serve(JSONResult{Content: "Hello"})
+
+	this.So(this.response.Code, should.Equal, http.StatusOK)
+	this.So(this.response.Header().Get(contentTypeHeader), should.Equal, jsonContentType)
+	this.So(this.response.Header().Get(contentLengthHeader), should.Equal, "8")
+	this.So(this.response.Body.Len(), should.Equal, 0)
+}
+
+func (this *ResponseBufferFixture) TestNoContent_BodyDiscardedAndReported() {
+	this.serve(CompoundRenderer{StatusCodeRenderer(http.StatusNoContent), StringBodyRenderer("oops")})
+
+	this.So(this.response.Code, should.Equal, http.StatusNoContent)
+	this.So(this.response.Header().Get(contentLengthHeader), should.BeBlank)
+	this.So(this.response.Body.Len(), should.Equal, 0)
+	this.So(this.logged.String(), should.ContainSubstring,
+		"detour: discarded body (4 bytes) written to a [204] response for [GET /path]")
+}
+
+func (this *ResponseBufferFixture) TestNotModified_BodyDiscarded() {
+	this.serve(CompoundRenderer{StatusCodeRenderer(http.StatusNotModified), StringBodyRenderer("oops")})
+
+	this.So(this.response.Code, should.Equal, http.StatusNotModified)
+	this.So(this.response.Body.Len(), should.Equal, 0)
+	this.So(this.logged.String(), should.ContainSubstring, "[304]")
+}
+
+func (this *ResponseBufferFixture) TestBufferResetAfterFlush() {
+	buffer := newResponseBuffer()
+	buffer.Header().Set("A", "1")
+	buffer.WriteHeader(http.StatusTeapot)
+	_, _ = buffer.Write([]byte("body"))
+
+	buffer.flush(this.response, this.request)
+
+	this.So(buffer.StatusCode(), should.Equal, http.StatusOK)
+	this.So(buffer.Header(), should.BeEmpty)
+	this.So(buffer.body.Len(), should.Equal, 0)
+}
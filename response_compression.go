@@ -0,0 +1,170 @@
+package detour
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompressResponses gzips (or deflates) response bodies of at least the minimum
+// size (in bytes) when the client accepts it and the content type is textual.
+func CompressResponses(minimumSize int) Option {
+	return func(this *actionHandler) { this.compressionThreshold = minimumSize }
+}
+
+// compress replaces the buffered body with an encoded version when appropriate,
+// adjusting the Content-Encoding, Vary, and ETag headers accordingly.
+func (this *responseBuffer) compress(acceptEncoding string, minimumSize int) {
+	if minimumSize <= 0 || !this.isCompressibleContent() {
+		return
+	}
+	this.headers.Set(varyHeader, appendToken(this.headers.Get(varyHeader), acceptEncodingHeader))
+	if !this.isCompressibleResponse() || this.spill != nil || this.body.Len() < minimumSize {
+		return // spilled bodies are streamed from disk as-is
+	}
+
+	encoding := negotiateEncoding(acceptEncoding)
+	if len(encoding) == 0 {
+		return
+	}
+
+	this.scratch.Reset()
+	encoder := acquireEncoder(encoding, this.scratch)
+	_, _ = this.body.WriteTo(encoder)
+	_ = encoder.Close()
+	releaseEncoder(encoding, encoder)
+	this.body, this.scratch = this.scratch, this.body
+
+	this.headers.Set(contentEncodingHeader, encoding)
+	this.headers.Del(contentLengthHeader)
+	if etag := this.headers.Get(etagHeader); strings.HasPrefix(etag, `"`) {
+		this.headers.Set(etagHeader, "W/"+etag) // the encoded body is no longer byte-for-byte the same
+	}
+}
+
+func (this *responseBuffer) isCompressibleContent() bool {
+	if len(this.headers.Get(contentEncodingHeader)) > 0 {
+		return false
+	}
+	return isCompressibleContentType(this.headers.Get(contentTypeHeader))
+}
+
+// isCompressibleResponse rejects responses without a body and those which are (or
+// advertise support for) byte ranges, which must address the identity encoding
+// lest a resumed download splice encoded and unencoded bytes together.
+func (this *responseBuffer) isCompressibleResponse() bool {
+	switch this.statusCode {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+	if len(this.headers.Get(contentRangeHeader)) > 0 {
+		return false
+	}
+	acceptRanges := this.headers.Get(acceptRangesHeader)
+	return len(acceptRanges) == 0 || strings.EqualFold(acceptRanges, "none")
+}
+
+func isCompressibleContentType(contentType string) bool {
+	media := mediaType(contentType)
+	if strings.HasPrefix(media, "text/") {
+		return true
+	}
+	for _, suffix := range compressibleSuffixes {
+		if strings.HasSuffix(media, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateEncoding chooses gzip or deflate (in that order of preference) unless
+// forbidden (q=0) by the Accept-Encoding header.
+func negotiateEncoding(acceptEncoding string) string {
+	qualities := make(map[string]float64)
+	for _, item := range strings.Split(acceptEncoding, ",") {
+		coding, params, err := mime.ParseMediaType(strings.TrimSpace(item))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if value, found := params["q"]; found {
+			quality, _ = strconv.ParseFloat(value, 64)
+		}
+		qualities[coding] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range []string{gzipEncoding, deflateEncoding} {
+		quality, found := qualities[encoding]
+		if !found {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+func appendToken(list, token string) string {
+	for _, existing := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(existing), token) {
+			return list
+		}
+	}
+	if len(strings.TrimSpace(list)) == 0 {
+		return token
+	}
+	return list + ", " + token
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var (
+	gzipWriters = sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }}
+	zlibWriters = sync.Pool{New: func() interface{} { return zlib.NewWriter(nil) }}
+)
+
+type resettableEncoder interface {
+	io.WriteCloser
+	Reset(io.Writer)
+}
+
+func acquireEncoder(encoding string, destination *bytes.Buffer) resettableEncoder {
+	var encoder resettableEncoder
+	if encoding == gzipEncoding {
+		encoder = gzipWriters.Get().(*gzip.Writer)
+	} else {
+		encoder = zlibWriters.Get().(*zlib.Writer)
+	}
+	encoder.Reset(destination)
+	return encoder
+}
+func releaseEncoder(encoding string, encoder resettableEncoder) {
+	encoder.Reset(nil)
+	if encoding == gzipEncoding {
+		gzipWriters.Put(encoder)
+	} else {
+		zlibWriters.Put(encoder)
+	}
+}
+
+var compressibleSuffixes = []string{"/json", "+json", "/javascript", "/xml", "+xml", "/x-www-form-urlencoded"}
+
+const (
+	gzipEncoding           = "gzip"
+	deflateEncoding        = "deflate"
+	varyHeader             = "Vary"
+	acceptRangesHeader     = "Accept-Ranges"
+	contentRangeHeader     = "Content-Range"
+	acceptEncodingHeader   = "Accept-Encoding"
+	contentEncodingHeader  = "Content-Encoding"
+	contentLengthHeader    = "Content-Length"
+	transferEncodingHeader = "Transfer-Encoding"
+)
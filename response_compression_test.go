@@ -0,0 +1,158 @@
+package detour
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestCompressionFixture(t *testing.T) {
+	gunit.Run(new(CompressionFixture), t)
+}
+
+type CompressionFixture struct {
+	*gunit.Fixture
+
+	request  *http.Request
+	response *httptest.ResponseRecorder
+	content  string
+}
+
+func (this *CompressionFixture) Setup() {
+	this.request = httptest.NewRequest("GET", "/", nil)
+	this.request.Header.Set(acceptEncodingHeader, "gzip, deflate")
+	this.response = httptest.NewRecorder()
+	this.content = strings.Repeat("Hello, World! ", 100)
+}
+
+func (this *CompressionFixture) serve(result Renderer) {
+	New(func() Renderer { return result }, CompressResponses(1024)).ServeHTTP(this.response, this.request)
+}
+func (this *CompressionFixture) decoded(reader func(io.Reader) (io.Reader, error)) string {
+	decoder, err := reader(this.response.Body)
+	this.So(err, should.BeNil)
+	all, _ := ioutil.ReadAll(decoder)
+	return string(all)
+}
+func (this *CompressionFixture) assertUncompressed() {
+	this.So(this.response.Header().Get(contentEncodingHeader), should.BeBlank)
+	this.So(this.response.Body.String(), should.Equal, this.content)
+}
+
+func (this *CompressionFixture) TestGzip() {
+	this.serve(ContentResult{Content: this.content, ETag: "v1", Headers: map[string]string{"Vary": "Origin"}})
+
+	this.So(this.response.Header().Get(contentEncodingHeader), should.Equal, "gzip")
+	this.So(this.response.Header().Get(varyHeader), should.Equal, "Origin, Accept-Encoding")
+	this.So(this.response.Header().Get(etagHeader), should.Equal, `W/"v1"`)
+	this.So(this.response.Body.Len(), should.BeLessThan, len(this.content))
+	this.So(this.decoded(func(reader io.Reader) (io.Reader, error) { return gzip.NewReader(reader) }), should.Equal, this.content)
+}
+
+func (this *CompressionFixture) TestDeflate() {
+	this.request.Header.Set(acceptEncodingHeader, "gzip;q=0.5, deflate")
+
+	this.serve(JSONResult{Content: this.content})
+
+	this.So(this.response.Header().Get(contentEncodingHeader), should.Equal, "deflate")
+	this.So(this.decoded(func(reader io.Reader) (io.Reader, error) { return zlib.NewReader(reader) }), should.Equal, `"`+this.content+`"`+"\n")
+}
+
+func (this *CompressionFixture) TestBufferReusedAcrossRequests() {
+	handler := New(func() Renderer { return ContentResult{Content: this.content} }, CompressResponses(1))
+	for x := 0; x < 3; x++ {
+		this.response = httptest.NewRecorder()
+		handler.ServeHTTP(this.response, this.request)
+		this.So(this.decoded(func(reader io.Reader) (io.Reader, error) { return gzip.NewReader(reader) }), should.Equal, this.content)
+	}
+}
+
+func (this *CompressionFixture) TestNotAccepted_VaryStillSet() {
+	this.request.Header.Set(acceptEncodingHeader, "gzip;q=0, br")
+
+	this.serve(ContentResult{Content: this.content})
+
+	this.assertUncompressed()
+	this.So(this.response.Header().Get(varyHeader), should.Equal, "Accept-Encoding")
+}
+
+func (this *CompressionFixture) TestWildcardAccepted() {
+	this.request.Header.Set(acceptEncodingHeader, "*")
+	this.serve(ContentResult{Content: this.content})
+	this.So(this.response.Header().Get(contentEncodingHeader), should.Equal, "gzip")
+}
+
+func (this *CompressionFixture) TestBelowThreshold() {
+	this.content = "small"
+	this.serve(ContentResult{Content: this.content})
+	this.assertUncompressed()
+}
+
+func (this *CompressionFixture) TestIncompressibleContentType() {
+	this.serve(BinaryResult{Content: []byte(this.content), ContentType: "image/png"})
+	this.assertUncompressed()
+	this.So(this.response.Header().Get(varyHeader), should.BeBlank)
+}
+
+func (this *CompressionFixture) TestAlreadyEncoded() {
+	this.serve(CompoundRenderer{
+		SetHeaderPairsRenderer{contentEncodingHeader, "br"},
+		ContentResult{Content: this.content},
+	})
+
+	this.So(this.response.Header().Get(contentEncodingHeader), should.Equal, "br")
+	this.So(this.response.Body.String(), should.Equal, this.content)
+}
+
+func (this *CompressionFixture) TestRangeCapableResponse_NotCompressed() {
+	this.serve(FileResult{Name: "a.txt", Content: []byte(this.content)})
+
+	this.assertUncompressed()
+	this.So(this.response.Header().Get(acceptRangesHeader), should.Equal, "bytes")
+	this.So(this.response.Header().Get(varyHeader), should.Equal, "Accept-Encoding")
+	this.So(this.response.Header().Get(contentLengthHeader), should.Equal, strconv.Itoa(len(this.content)))
+}
+
+func (this *CompressionFixture) TestPartialContent_NotCompressed_VarySet() {
+	this.request.Header.Set("Range", "bytes=100-")
+
+	this.serve(FileResult{Name: "a.txt", Content: []byte(this.content)})
+
+	this.So(this.response.Code, should.Equal, http.StatusPartialContent)
+	this.So(this.response.Header().Get(contentEncodingHeader), should.BeBlank)
+	this.So(this.response.Header().Get(varyHeader), should.Equal, "Accept-Encoding")
+	this.So(this.response.Body.String(), should.Equal, this.content[100:])
+}
+
+func (this *CompressionFixture) TestAcceptRangesNone_Compressed() {
+	this.serve(CompoundRenderer{
+		SetHeaderPairsRenderer{acceptRangesHeader, "none"},
+		ContentResult{Content: this.content},
+	})
+
+	this.So(this.response.Header().Get(contentEncodingHeader), should.Equal, "gzip")
+}
+
+func (this *CompressionFixture) TestDisabledByDefault() {
+	New(func() Renderer { return ContentResult{Content: this.content} }).ServeHTTP(this.response, this.request)
+	this.assertUncompressed()
+}
+
+func (this *CompressionFixture) TestCompressibleContentTypes() {
+	this.So(isCompressibleContentType("text/html; charset=utf-8"), should.BeTrue)
+	this.So(isCompressibleContentType("application/json"), should.BeTrue)
+	this.So(isCompressibleContentType("application/problem+json"), should.BeTrue)
+	this.So(isCompressibleContentType("application/javascript"), should.BeTrue)
+	this.So(isCompressibleContentType("image/svg+xml"), should.BeTrue)
+	this.So(isCompressibleContentType("application/octet-stream"), should.BeFalse)
+	this.So(isCompressibleContentType(""), should.BeFalse)
+}
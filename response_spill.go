@@ -0,0 +1,68 @@
+package detour
+
+import (
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+)
+
+// SpillResponses caps the memory used to buffer each response body at the threshold
+// (in bytes), beyond which the body is buffered in a temporary file instead. Nothing
+// is written to the client until the entire response has been rendered, as usual.
+// Spilled bodies are never compressed (see CompressResponses).
+func SpillResponses(threshold int) Option {
+	return func(this *actionHandler) { this.spillThreshold = threshold }
+}
+
+// spillWrite writes to the temporary file, first moving any buffered body there.
+func (this *responseBuffer) spillWrite(p []byte) (int, error) {
+	if this.spill == nil {
+		if this.spill, this.spillErr = ioutil.TempFile("", "detour-response-"); this.spillErr != nil {
+			return 0, this.spillErr
+		}
+		if this.spilled, this.spillErr = this.body.WriteTo(this.spill); this.spillErr != nil {
+			return 0, this.spillErr
+		}
+	}
+
+	n, err := this.spill.Write(p)
+	this.spilled += int64(n)
+	this.spillErr = err
+	return n, err
+}
+
+// length is the combined length of the body buffered in memory and on disk.
+func (this *responseBuffer) length() int64 {
+	return int64(this.body.Len()) + this.spilled
+}
+
+func (this *responseBuffer) writeBody(response http.ResponseWriter) {
+	if this.spill == nil {
+		_, _ = io.Copy(response, this.body)
+	} else if _, err := this.spill.Seek(0, io.SeekStart); err == nil {
+		_, _ = io.Copy(response, this.spill)
+	}
+}
+
+// failedSpill responds in place of a body that couldn't be completely spilled.
+func (this *responseBuffer) failedSpill(response http.ResponseWriter, request *http.Request) {
+	log.Printf("detour: failed to spill the response body for [%s %s]: %s", request.Method, request.URL.Path, this.spillErr)
+	http.Error(response, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+func (this *responseBuffer) discardBody() {
+	this.body.Reset()
+	this.removeSpill()
+}
+
+func (this *responseBuffer) removeSpill() {
+	if this.spill != nil {
+		_ = this.spill.Close()
+		_ = os.Remove(this.spill.Name())
+	}
+	this.spill = nil
+	this.spilled = 0
+	this.spillErr = nil
+}
@@ -0,0 +1,112 @@
+package detour
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/smartystreets/assertions/should"
+)
+
+func (this *ResponseBufferFixture) TestBelowSpillThreshold_BufferedInMemory() {
+	buffer := newResponseBuffer()
+	buffer.spillThreshold = 10
+
+	_, _ = buffer.Write([]byte("0123456789"))
+
+	this.So(buffer.spill, should.BeNil)
+	this.So(buffer.length(), should.Equal, 10)
+}
+
+func (this *ResponseBufferFixture) TestAboveSpillThreshold_BufferedBodyMovedToTemporaryFile() {
+	buffer := newResponseBuffer()
+	buffer.spillThreshold = 10
+
+	_, _ = buffer.Write([]byte("01234"))
+	_, _ = buffer.Write([]byte("56789A"))
+	_, _ = buffer.Write([]byte("BC"))
+
+	this.So(buffer.spill, should.NotBeNil)
+	this.So(buffer.body.Len(), should.Equal, 0)
+	this.So(buffer.length(), should.Equal, 13)
+	name := buffer.spill.Name()
+	this.So(name, should.StartWith, os.TempDir())
+
+	buffer.flush(this.response, this.request)
+
+	this.So(this.response.Body.String(), should.Equal, "0123456789ABC")
+	this.So(this.response.Header().Get(contentLengthHeader), should.Equal, "13")
+	this.So(buffer.spill, should.BeNil)
+	this.So(buffer.length(), should.Equal, 0)
+	_, err := os.Stat(name)
+	this.So(os.IsNotExist(err), should.BeTrue)
+}
+
+func (this *ResponseBufferFixture) TestSpilledResponse_Served() {
+	content := strings.Repeat("0123456789", 1000)
+
+	this.serve(ReaderBodyRenderer{Reader: strings.NewReader(content)}, SpillResponses(1024))
+
+	this.So(this.response.Code, should.Equal, http.StatusOK)
+	this.So(this.response.Header().Get(contentLengthHeader), should.Equal, "10000")
+	this.So(this.response.Body.String(), should.Equal, content)
+}
+
+func (this *ResponseBufferFixture) TestSpilledResponse_HEAD_ContentLengthOnly() {
+	this.request.Method = http.MethodHead
+
+	this.serve(BytesBodyRenderer(bytes.Repeat([]byte("x"), 2048)), SpillResponses(1024))
+
+	this.So(this.response.Header().Get(contentLengthHeader), should.Equal, "2048")
+	this.So(this.response.Body.Len(), should.Equal, 0)
+}
+
+func (this *ResponseBufferFixture) TestSpilledResponse_NotCompressed() {
+	this.request.Header.Set(acceptEncodingHeader, "gzip")
+
+	this.serve(ContentResult{Content: strings.Repeat("a", 2048)}, SpillResponses(1024), CompressResponses(1))
+
+	this.So(this.response.Header().Get(contentEncodingHeader), should.BeBlank)
+	this.So(this.response.Body.Len(), should.Equal, 2048)
+}
+
+func (this *ResponseBufferFixture) TestSpilledBodyOfNoContentResponse_Discarded() {
+	buffer := newResponseBuffer()
+	buffer.spillThreshold = 1
+	buffer.WriteHeader(http.StatusNoContent)
+	_, _ = buffer.Write([]byte("discarded"))
+	name := buffer.spill.Name()
+
+	buffer.flush(this.response, this.request)
+
+	this.So(this.response.Body.Len(), should.Equal, 0)
+	this.So(this.logged.String(), should.ContainSubstring, "discarded body (9 bytes)")
+	_, err := os.Stat(name)
+	this.So(os.IsNotExist(err), should.BeTrue)
+}
+
+func (this *ResponseBufferFixture) TestSpillFailure_InternalServerErrorBeforeAnythingWritten() {
+	defer os.Setenv("TMPDIR", os.Getenv("TMPDIR"))
+	_ = os.Setenv("TMPDIR", "/does/not/exist")
+
+	this.serve(CompoundRenderer{
+		SetHeaderPairsRenderer{"X-Partial", "yes"},
+		StatusCodeRenderer(http.StatusCreated),
+		StringBodyRenderer("this body exceeds the threshold"),
+	}, SpillResponses(10))
+
+	this.So(this.response.Code, should.Equal, http.StatusInternalServerError)
+	this.So(this.response.Header().Get("X-Partial"), should.BeBlank)
+	this.So(this.response.Body.String(), should.Equal, "Internal Server Error\n")
+	this.So(this.logged.String(), should.ContainSubstring, "detour: failed to spill the response body for [GET /path]")
+}
+
+func (this *ResponseBufferFixture) TestSpillThresholdResetForReuse() {
+	buffer := newResponseBuffer()
+	buffer.spillThreshold = 1
+
+	buffer.initialize()
+
+	this.So(buffer.spillThreshold, should.Equal, 0)
+}
@@ -0,0 +1,122 @@
+package detour
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"reflect"
+)
+
+// ResponseDeclaration describes one of the responses a controller action may render.
+type ResponseDeclaration struct {
+	StatusCode  int
+	ContentType string       // blank matches any content type
+	Type        reflect.Type // nil matches any (or no) content
+}
+
+// DeclareResponse records one of the possible responses of the handler. The content
+// parameter is a sample of the rendered content (ie. (*UserView)(nil)) and is only
+// used to identify its type. Declared responses are included in OpenAPI documents
+// and, depending on the ContractMode (see VerifyResponses), are enforced at runtime.
+func DeclareResponse(statusCode int, contentType string, content interface{}) Option {
+	return func(this *actionHandler) {
+		this.responses = append(this.responses, ResponseDeclaration{
+			StatusCode:  orOK(statusCode),
+			ContentType: contentType,
+			Type:        reflect.TypeOf(content),
+		})
+	}
+}
+
+type ContractMode int
+
+const (
+	ContractIgnore ContractMode = iota
+	ContractLog
+	ContractPanic
+)
+
+// VerifyResponses compares each response rendered by the controller action with
+// the declared responses (see DeclareResponse), logging or panicking (according
+// to the mode) when no declaration matches. It's intended for use in development
+// and testing. Responses that result from binding or validation failures are not
+// verified.
+func VerifyResponses(mode ContractMode) Option {
+	return func(this *actionHandler) { this.contract = mode }
+}
+
+func (this *actionHandler) verifyResponse(result Renderer, buffer *responseBuffer, request *http.Request) {
+	if this.contract == ContractIgnore || len(this.responses) == 0 {
+		return
+	}
+
+	contentType := buffer.Header().Get(contentTypeHeader)
+	content, inspected := renderedContentType(result)
+	for _, declared := range this.responses {
+		if declared.matches(buffer.StatusCode(), contentType, content, inspected) {
+			return
+		}
+	}
+
+	message := fmt.Sprintf("detour: [%s %s] rendered an undeclared response: [%d] [%s] [%v]",
+		request.Method, request.URL.Path, buffer.StatusCode(), contentType, content)
+	if this.contract == ContractPanic {
+		panic(message)
+	}
+	log.Println(message)
+}
+
+func (this ResponseDeclaration) matches(statusCode int, contentType string, content reflect.Type, inspected bool) bool {
+	if this.StatusCode != statusCode {
+		return false
+	}
+	if len(this.ContentType) > 0 && mediaType(this.ContentType) != mediaType(contentType) {
+		return false
+	}
+	if this.Type == nil || !inspected {
+		return true
+	}
+	return dereference(this.Type) == dereference(content)
+}
+
+func renderedContentType(result Renderer) (reflect.Type, bool) {
+	switch typed := result.(type) {
+	case JSONResult:
+		return reflect.TypeOf(typed.Content), true
+	case *JSONResult:
+		return reflect.TypeOf(typed.Content), true
+	case JSONPResult:
+		return reflect.TypeOf(typed.Content), true
+	case *JSONPResult:
+		return reflect.TypeOf(typed.Content), true
+	case JSONBodyRenderer:
+		return reflect.TypeOf(typed.Content), true
+	case *JSONBodyRenderer:
+		return reflect.TypeOf(typed.Content), true
+	case XMLBodyRenderer:
+		return reflect.TypeOf(typed.Content), true
+	case CompoundRenderer:
+		for _, inner := range typed {
+			if content, inspected := renderedContentType(inner); inspected {
+				return content, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func mediaType(contentType string) string {
+	parsed, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return parsed
+}
+
+func dereference(target reflect.Type) reflect.Type {
+	for target != nil && target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+	return target
+}
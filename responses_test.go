@@ -0,0 +1,128 @@
+package detour
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestResponseContractFixture(t *testing.T) {
+	gunit.Run(new(ResponseContractFixture), t)
+}
+
+type ResponseContractFixture struct {
+	*gunit.Fixture
+
+	request  *http.Request
+	response *httptest.ResponseRecorder
+	logged   *bytes.Buffer
+}
+
+func (this *ResponseContractFixture) Setup() {
+	this.request = httptest.NewRequest("GET", "/users", nil)
+	this.response = httptest.NewRecorder()
+	this.logged = new(bytes.Buffer)
+	log.SetOutput(this.logged)
+}
+func (this *ResponseContractFixture) Teardown() {
+	log.SetOutput(os.Stderr)
+}
+
+func (this *ResponseContractFixture) serve(result Renderer, options ...Option) {
+	New(func() Renderer { return result }, options...).ServeHTTP(this.response, this.request)
+}
+
+func (this *ResponseContractFixture) TestDeclaredResponsesRecordedOnRoute() {
+	registry := NewRegistry()
+	registry.Handle("GET", "/", New(func() Renderer { return nil },
+		DeclareResponse(0, jsonContentType, (*ContractView)(nil)),
+		DeclareResponse(http.StatusNotFound, "", nil),
+	))
+
+	routes := registry.Routes()
+
+	this.So(routes[0].Responses, should.HaveLength, 2)
+	this.So(routes[0].Responses[0].StatusCode, should.Equal, http.StatusOK)
+	this.So(routes[0].Responses[0].Type.String(), should.Equal, "*detour.ContractView")
+	this.So(routes[0].Responses[1].Type, should.BeNil)
+}
+
+func (this *ResponseContractFixture) TestMatchingResponse_NothingLogged() {
+	this.serve(JSONResult{Content: &ContractView{}},
+		DeclareResponse(http.StatusOK, "application/json", ContractView{}),
+		VerifyResponses(ContractLog))
+
+	this.So(this.response.Code, should.Equal, http.StatusOK)
+	this.So(this.logged.String(), should.BeBlank)
+}
+
+func (this *ResponseContractFixture) TestMatchingCompoundResponse_NothingLogged() {
+	this.serve(CompoundRenderer{HeadersRenderer{"A": {"1"}}, &JSONResult{StatusCode: 201, Content: &ContractView{}}},
+		DeclareResponse(http.StatusCreated, jsonContentType, (*ContractView)(nil)),
+		VerifyResponses(ContractPanic))
+
+	this.So(this.response.Code, should.Equal, http.StatusCreated)
+}
+
+func (this *ResponseContractFixture) TestUninspectableContent_StatusAndContentTypeSuffice() {
+	this.serve(ContentResult{Content: "hi"},
+		DeclareResponse(http.StatusOK, plaintextContentType, ContractView{}),
+		VerifyResponses(ContractPanic))
+
+	this.So(this.response.Body.String(), should.Equal, "hi")
+}
+
+func (this *ResponseContractFixture) TestUndeclaredStatusCode_Logged() {
+	this.serve(JSONResult{StatusCode: http.StatusTeapot, Content: &ContractView{}},
+		DeclareResponse(http.StatusOK, "", ContractView{}),
+		VerifyResponses(ContractLog))
+
+	this.So(this.response.Code, should.Equal, http.StatusTeapot)
+	this.So(this.logged.String(), should.ContainSubstring,
+		"detour: [GET /users] rendered an undeclared response: [418] [application/json; charset=utf-8] [*detour.ContractView]")
+}
+
+func (this *ResponseContractFixture) TestUndeclaredContentType_Panics() {
+	this.So(func() {
+		this.serve(JSONResult{Content: "wrong"},
+			DeclareResponse(http.StatusOK, "", ContractView{}),
+			VerifyResponses(ContractPanic))
+	}, should.PanicWith, "detour: [GET /users] rendered an undeclared response: [200] [application/json; charset=utf-8] [string]")
+}
+
+func (this *ResponseContractFixture) TestWrongMediaType_Panics() {
+	this.So(func() {
+		this.serve(ContentResult{Content: "hi"},
+			DeclareResponse(http.StatusOK, "application/json", nil),
+			VerifyResponses(ContractPanic))
+	}, should.Panic)
+}
+
+func (this *ResponseContractFixture) TestVerificationDisabledByDefault() {
+	this.serve(StatusCodeResult{StatusCode: http.StatusTeapot}, DeclareResponse(http.StatusOK, "", nil))
+
+	this.So(this.response.Code, should.Equal, http.StatusTeapot)
+	this.So(this.logged.String(), should.BeBlank)
+}
+
+func (this *ResponseContractFixture) TestInputModelErrorsNotVerified() {
+	handler := New((&Controller{}).HandleBindingFailsInputModel,
+		DeclareResponse(http.StatusOK, "", nil),
+		VerifyResponses(ContractPanic))
+
+	handler.ServeHTTP(this.response, this.request)
+
+	this.So(this.response.Code, should.Equal, http.StatusBadRequest)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type ContractView struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,43 @@
+package detour
+
+import (
+	"context"
+	"net/http"
+)
+
+// settings govern the behavior of renderers. The package-level defaults may be
+// overridden for individual handlers via Options, in which case the handler
+// makes the resulting settings available to renderers through the request context.
+type settings struct {
+	verboseDiagnostics bool
+	jsonEncoding       JSONEncoding
+	jsonCodec          JSONCodec // nil means StandardJSONCodec
+	callbackParameter  string
+}
+
+// defaultSettings should only be modified during initialization (like RegisterTemplates).
+var defaultSettings settings
+
+type setting func(*settings)
+
+type settingsKey struct{}
+
+func (this *actionHandler) applySettings(request *http.Request) *http.Request {
+	if len(this.settings) == 0 {
+		return request
+	}
+	resolved := defaultSettings
+	for _, apply := range this.settings {
+		apply(&resolved)
+	}
+	return request.WithContext(context.WithValue(request.Context(), settingsKey{}, resolved))
+}
+
+func currentSettings(request *http.Request) settings {
+	if request != nil {
+		if resolved, ok := request.Context().Value(settingsKey{}).(settings); ok {
+			return resolved
+		}
+	}
+	return defaultSettings
+}
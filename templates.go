@@ -0,0 +1,94 @@
+package detour
+
+import (
+	"html/template"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+)
+
+// Templates is a set of html/template pages which share layouts and partials.
+// Each page is parsed (once, unless Reload is set) along with the Shared files
+// so that every page may define the blocks (ie. "content") its layout expects.
+type Templates struct {
+	Files  TemplateFiles    // an embed.FS satisfies this interface, as does TemplateDirectory
+	Shared []string         // layouts and partials parsed along with every page
+	Layout string           // the name of the template executed for each page (blank: the page itself)
+	Funcs  template.FuncMap // made available to all templates
+	Reload bool             // re-read and re-parse files for every execution (for development)
+
+	mutex sync.Mutex
+	pages map[string]*template.Template
+}
+
+// TemplateFiles provides template source by (slash-separated) file name.
+type TemplateFiles interface {
+	ReadFile(name string) ([]byte, error)
+}
+
+// TemplateDirectory reads template files from disk, relative to the directory.
+type TemplateDirectory string
+
+func (this TemplateDirectory) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(string(this), filepath.FromSlash(name)))
+}
+
+var registeredTemplates *Templates
+
+// RegisterTemplates sets the Templates used by any TemplateResult that doesn't specify its own.
+func RegisterTemplates(templates *Templates) {
+	registeredTemplates = templates
+}
+
+// Execute renders the named page (a file name) with the data to the writer.
+func (this *Templates) Execute(writer io.Writer, page string, data interface{}) error {
+	parsed, err := this.lookup(page)
+	if err != nil {
+		return err
+	}
+	return parsed.ExecuteTemplate(writer, firstNonBlank(this.Layout, page), data)
+}
+
+func (this *Templates) lookup(page string) (*template.Template, error) {
+	if this.Reload {
+		return this.parse(page)
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if parsed, found := this.pages[page]; found {
+		return parsed, nil
+	}
+	parsed, err := this.parse(page)
+	if err != nil {
+		return nil, err
+	}
+	if this.pages == nil {
+		this.pages = make(map[string]*template.Template)
+	}
+	this.pages[page] = parsed
+	return parsed, nil
+}
+
+func (this *Templates) parse(page string) (*template.Template, error) {
+	set := template.New(page).Funcs(this.Funcs)
+	for _, name := range this.Shared {
+		if err := this.parseFile(set.New(name), name); err != nil {
+			return nil, err
+		}
+	}
+	if err := this.parseFile(set, page); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+func (this *Templates) parseFile(target *template.Template, name string) error {
+	source, err := this.Files.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	_, err = target.Parse(string(source))
+	return err
+}
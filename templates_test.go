@@ -0,0 +1,119 @@
+package detour
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestTemplatesFixture(t *testing.T) {
+	gunit.Run(new(TemplatesFixture), t)
+}
+
+type TemplatesFixture struct {
+	*gunit.Fixture
+
+	files     FakeTemplateFiles
+	templates *Templates
+}
+
+func (this *TemplatesFixture) Setup() {
+	this.files = FakeTemplateFiles{
+		"layout.html":  `<main>{{template "content" .}}</main>{{template "footer"}}`,
+		"footer.html":  `{{define "footer"}}<footer>{{shout "bye"}}</footer>{{end}}`,
+		"hello.html":   `{{define "content"}}Hello, {{.}}!{{end}}`,
+		"goodbye.html": `{{define "content"}}Goodbye, {{.}}!{{end}}`,
+	}
+	this.templates = &Templates{
+		Files:  this.files,
+		Shared: []string{"layout.html", "footer.html"},
+		Layout: "layout.html",
+		Funcs:  template.FuncMap{"shout": strings.ToUpper},
+	}
+}
+
+func (this *TemplatesFixture) execute(page string, data interface{}) (string, error) {
+	buffer := new(bytes.Buffer)
+	err := this.templates.Execute(buffer, page, data)
+	return buffer.String(), err
+}
+
+func (this *TemplatesFixture) TestPagesShareLayoutAndPartials() {
+	hello, err := this.execute("hello.html", "<World>")
+	this.So(err, should.BeNil)
+	this.So(hello, should.Equal, "<main>Hello, &lt;World&gt;!</main><footer>BYE</footer>")
+
+	goodbye, err := this.execute("goodbye.html", "World")
+	this.So(err, should.BeNil)
+	this.So(goodbye, should.Equal, "<main>Goodbye, World!</main><footer>BYE</footer>")
+}
+
+func (this *TemplatesFixture) TestWithoutLayout_PageExecutedDirectly() {
+	this.templates = &Templates{Files: FakeTemplateFiles{"page.html": "<p>{{.}}</p>"}}
+
+	page, err := this.execute("page.html", 42)
+
+	this.So(err, should.BeNil)
+	this.So(page, should.Equal, "<p>42</p>")
+}
+
+func (this *TemplatesFixture) TestParsedPagesCached() {
+	_, _ = this.execute("hello.html", "World")
+	this.files["hello.html"] = `{{define "content"}}Changed{{end}}`
+
+	hello, _ := this.execute("hello.html", "World")
+
+	this.So(hello, should.ContainSubstring, "Hello, World!")
+}
+
+func (this *TemplatesFixture) TestReload_ChangesPickedUp() {
+	this.templates.Reload = true
+	_, _ = this.execute("hello.html", "World")
+	this.files["hello.html"] = `{{define "content"}}Changed{{end}}`
+
+	hello, _ := this.execute("hello.html", "World")
+
+	this.So(hello, should.ContainSubstring, "Changed")
+}
+
+func (this *TemplatesFixture) TestMissingFile() {
+	_, err := this.execute("missing.html", nil)
+	this.So(err, should.NotBeNil)
+}
+
+func (this *TemplatesFixture) TestParseFailure() {
+	this.files["broken.html"] = `{{define "content"}}{{.Unclosed{{end}}`
+	_, err := this.execute("broken.html", nil)
+	this.So(err, should.NotBeNil)
+}
+
+func (this *TemplatesFixture) TestTemplateDirectory() {
+	directory, _ := ioutil.TempDir("", "detour-templates")
+	defer func() { _ = os.RemoveAll(directory) }()
+	_ = os.MkdirAll(filepath.Join(directory, "users"), 0700)
+	_ = ioutil.WriteFile(filepath.Join(directory, "users", "index.html"), []byte("users"), 0600)
+
+	content, err := TemplateDirectory(directory).ReadFile("users/index.html")
+
+	this.So(err, should.BeNil)
+	this.So(string(content), should.Equal, "users")
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type FakeTemplateFiles map[string]string
+
+func (this FakeTemplateFiles) ReadFile(name string) ([]byte, error) {
+	if content, found := this[name]; found {
+		return []byte(content), nil
+	}
+	return nil, errors.New("file not found: " + name)
+}
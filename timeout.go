@@ -0,0 +1,77 @@
+package detour
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Timeout limits the time allowed to prepare the input model and call the controller
+// action. The context provided to the model (see BindContext) carries the deadline.
+// The model is prepared on the handler's goroutine (so the request body is never read
+// after the handler returns), and the controller isn't called should the deadline pass
+// meanwhile. Should the deadline pass first, the renderer (503 Service Unavailable, if
+// nil) provides the response and the eventual result of the controller is discarded.
+func Timeout(duration time.Duration, renderer Renderer) Option {
+	if renderer == nil {
+		renderer = StatusCodeResult{StatusCode: http.StatusServiceUnavailable, Message: http.StatusText(http.StatusServiceUnavailable)}
+	}
+	return func(this *actionHandler) {
+		this.timeout = duration
+		this.timeoutRenderer = renderer
+	}
+}
+
+type attempt struct {
+	result    Renderer
+	recovered interface{}
+}
+
+// respondWithin responds with the deadline (if any) applied to the request.
+func (this *actionHandler) respondWithin(model interface{}, request *http.Request) (Renderer, bool) {
+	if this.timeout <= 0 {
+		return this.respond(model, request)
+	}
+	ctx, cancel := context.WithTimeout(request.Context(), this.timeout)
+	defer cancel()
+	return this.respond(model, request.WithContext(ctx))
+}
+
+// callWithin calls the controller on another goroutine (so as to stop waiting at the
+// deadline), and re-panics on this goroutine should the controller panic (or, should
+// it panic after the deadline, logs the panic).
+func (this *actionHandler) callWithin(model interface{}, request *http.Request) (Renderer, bool) {
+	if this.timeout <= 0 {
+		return this.controllerActionResult(model), true
+	}
+
+	attempts := make(chan attempt)
+	late := make(chan struct{}) // closed once we've stopped waiting for the attempt
+	go func() {
+		var result attempt
+		defer func() {
+			result.recovered = recover()
+			select {
+			case attempts <- result:
+			case <-late:
+				if result.recovered != nil {
+					log.Printf("detour: controller panicked after the deadline for [%s %s]: %v",
+						request.Method, request.URL.Path, result.recovered)
+				}
+			}
+		}()
+		result.result = this.controllerActionResult(model)
+	}()
+
+	select {
+	case result := <-attempts:
+		if result.recovered != nil {
+			panic(result.recovered)
+		}
+		return result.result, true
+	case <-request.Context().Done():
+		close(late)
+		return this.timeoutRenderer, false
+	}
+}
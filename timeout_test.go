@@ -0,0 +1,175 @@
+package detour
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/assertions/should"
+	"github.com/smartystreets/gunit"
+)
+
+func TestTimeoutFixture(t *testing.T) {
+	gunit.Run(new(TimeoutFixture), t)
+}
+
+type TimeoutFixture struct {
+	*gunit.Fixture
+
+	release  chan struct{}
+	released sync.Once
+	finished chan Renderer
+	response *httptest.ResponseRecorder
+	request  *http.Request
+}
+
+func (this *TimeoutFixture) Setup() {
+	this.release = make(chan struct{})
+	this.finished = make(chan Renderer, 1)
+	this.response = httptest.NewRecorder()
+	this.request = httptest.NewRequest("GET", "/", nil)
+}
+func (this *TimeoutFixture) Teardown() {
+	this.releaseAction()
+	log.SetOutput(os.Stderr)
+}
+
+func (this *TimeoutFixture) releaseAction() {
+	this.released.Do(func() { close(this.release) })
+}
+
+func (this *TimeoutFixture) slowAction(*TimeoutModel) Renderer {
+	<-this.release
+	result := StringBodyRenderer("late")
+	this.finished <- result
+	return result
+}
+
+func (this *TimeoutFixture) TestPromptController_ResultRendered() {
+	handler := New(func(model *TimeoutModel) Renderer {
+		deadline, ok := model.Context.Deadline()
+		this.So(ok, should.BeTrue)
+		this.So(deadline, should.HappenWithin, time.Second, time.Now())
+		return StringBodyRenderer("prompt")
+	}, Timeout(time.Second, nil))
+
+	handler.ServeHTTP(this.response, this.request)
+
+	this.So(this.response.Code, should.Equal, http.StatusOK)
+	this.So(this.response.Body.String(), should.Equal, "prompt")
+}
+
+func (this *TimeoutFixture) TestDeadlinePassed_DefaultServiceUnavailable() {
+	handler := New(this.slowAction, Timeout(time.Millisecond, nil))
+
+	handler.ServeHTTP(this.response, this.request)
+
+	this.So(this.response.Code, should.Equal, http.StatusServiceUnavailable)
+	this.So(this.response.Body.String(), should.Equal, "Service Unavailable")
+}
+
+func (this *TimeoutFixture) TestDeadlinePassed_CustomRenderer() {
+	handler := New(this.slowAction, Timeout(time.Millisecond, StatusCodeResult{StatusCode: http.StatusGatewayTimeout, Message: "too slow"}))
+
+	handler.ServeHTTP(this.response, this.request)
+
+	this.So(this.response.Code, should.Equal, http.StatusGatewayTimeout)
+	this.So(this.response.Body.String(), should.Equal, "too slow")
+}
+
+func (this *TimeoutFixture) TestLateResult_Discarded() {
+	handler := New(this.slowAction, Timeout(time.Millisecond, nil))
+	handler.ServeHTTP(this.response, this.request)
+
+	this.releaseAction()
+	<-this.finished
+
+	this.So(this.response.Code, should.Equal, http.StatusServiceUnavailable)
+	this.So(this.response.Body.String(), should.Equal, "Service Unavailable")
+}
+
+func (this *TimeoutFixture) TestContextCancelledAtDeadline() {
+	cancelled := make(chan error, 1)
+	handler := New(func(model *TimeoutModel) Renderer {
+		<-model.Context.Done()
+		cancelled <- model.Context.Err()
+		return nil
+	}, Timeout(time.Millisecond, nil))
+
+	handler.ServeHTTP(this.response, this.request)
+
+	this.So(<-cancelled, should.Resemble, context.DeadlineExceeded)
+}
+
+func (this *TimeoutFixture) TestSlowBinding_BodyReadBeforeReturning_ControllerNotCalled() {
+	model := new(SlowBindingModel)
+	called := false
+	handler := NewFromFactory(func() interface{} { return model }, func(*SlowBindingModel) Renderer {
+		called = true
+		return nil
+	}, Timeout(time.Millisecond, nil))
+	this.request = httptest.NewRequest("POST", "/", strings.NewReader("body"))
+
+	handler.ServeHTTP(this.response, this.request)
+
+	this.So(string(model.body), should.Equal, "body")
+	this.So(called, should.BeFalse)
+	this.So(this.response.Code, should.Equal, http.StatusServiceUnavailable)
+}
+
+func (this *TimeoutFixture) TestControllerPanic_RepanickedOnHandlerGoroutine() {
+	handler := New(func(*TimeoutModel) Renderer { panic("boink") }, Timeout(time.Second, nil))
+
+	this.So(func() { handler.ServeHTTP(this.response, this.request) }, should.PanicWith, "boink")
+}
+
+func (this *TimeoutFixture) TestLatePanic_Logged() {
+	logged := make(loggedLines, 1)
+	log.SetOutput(logged)
+	handler := New(func(*TimeoutModel) Renderer {
+		<-this.release
+		panic("boink")
+	}, Timeout(time.Millisecond, nil))
+	handler.ServeHTTP(this.response, this.request)
+
+	this.releaseAction()
+
+	this.So(<-logged, should.ContainSubstring, "detour: controller panicked after the deadline for [GET /]: boink")
+	this.So(this.response.Code, should.Equal, http.StatusServiceUnavailable)
+}
+
+func (this *TimeoutFixture) TestNoTimeout_NoDeadline() {
+	handler := New(func(model *TimeoutModel) Renderer {
+		_, ok := model.Context.Deadline()
+		this.So(ok, should.BeFalse)
+		return nil
+	})
+
+	handler.ServeHTTP(this.response, this.request)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type TimeoutModel struct{ ContextBinder }
+
+type SlowBindingModel struct{ body []byte }
+
+func (this *SlowBindingModel) Bind(request *http.Request) error {
+	time.Sleep(10 * time.Millisecond)
+	this.body, _ = ioutil.ReadAll(request.Body)
+	return nil
+}
+
+type loggedLines chan string
+
+func (this loggedLines) Write(p []byte) (int, error) {
+	this <- string(p)
+	return len(p), nil
+}